@@ -0,0 +1,104 @@
+package envconfig
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestArrayInline(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts   [3]string
+		Weights *[2]int
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"HOSTS": "a, b,c", "WEIGHTS": "1,2"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, [3]string{"a", "b", "c"}, spec.Hosts)
+	assert.Equal(t, [2]int{1, 2}, *spec.Weights)
+
+	options.Source = MapSource{"HOSTS": "a,b"}
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), "HOSTS: invalid length: expected 3 elements but got 2")
+}
+
+func TestArrayIndexed(t *testing.T) {
+	t.Parallel()
+
+	type Endpoint struct {
+		Host string
+		Port int
+	}
+	type TestSpec struct {
+		Endpoints [2]Endpoint
+		Hosts     [3]string
+	}
+	options := DefaultOptions()
+	options.Slice.FirstIndex = 1
+	options.Source = MapSource{
+		"ENDPOINTS_1_HOST": "a.local",
+		"ENDPOINTS_2_HOST": "b.local",
+		"ENDPOINTS_2_PORT": "8080",
+		"HOSTS_3":          "c",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, [2]Endpoint{{Host: "a.local"}, {Host: "b.local", Port: 8080}}, spec.Endpoints)
+	assert.Equal(t, [3]string{"", "", "c"}, spec.Hosts)
+}
+
+func TestArrayIndexOutOfBounds(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts [2]string
+	}
+
+	testCases := map[string]string{
+		"HOSTS_0": "HOSTS_0: invalid index: 0 is lower than first index 1",
+		"HOSTS_2": "",
+		"HOSTS_3": "HOSTS_3: invalid index: 3 exceeds last index 2",
+	}
+
+	for name, expected := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.Slice.FirstIndex = 1
+			options.Source = MapSource{name: "x"}
+
+			err := InitWithOptions(&TestSpec{}, options)
+			if expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, expected)
+			}
+		})
+	}
+}
+
+func TestArrayFlags(t *testing.T) {
+	type TestSpec struct {
+		Hosts [2]string
+	}
+
+	spec := TestSpec{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	assert.NoError(t, BindFlags(flags, &spec, DefaultOptions()))
+	assert.NoError(t, flags.Parse([]string{"-hosts", "a", "-hosts", "b"}))
+	assert.Equal(t, [2]string{"a", "b"}, spec.Hosts)
+
+	spec = TestSpec{}
+	flags = flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	assert.NoError(t, BindFlags(flags, &spec, DefaultOptions()))
+	assert.EqualError(t, flags.Parse([]string{"-hosts", "c", "-hosts", "d", "-hosts", "e"}),
+		`invalid value "e" for flag -hosts: invalid index: 2 exceeds last index 1`)
+	assert.Equal(t, [2]string{"c", "d"}, spec.Hosts)
+}
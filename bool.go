@@ -0,0 +1,64 @@
+package envconfig
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	extendedTrue  = []string{"yes", "y", "on", "enable", "enabled"}
+	extendedFalse = []string{"no", "n", "off", "disable", "disabled"}
+)
+
+func (o Options) parseBool(value string) (bool, error) {
+	if value == "" && o.Bool.Presence {
+		return true, nil
+	}
+	if containsFold(o.Bool.True, value) {
+		return true, nil
+	}
+	if containsFold(o.Bool.False, value) {
+		return false, nil
+	}
+
+	boolValue, err := strconv.ParseBool(value)
+	if err == nil || !o.Bool.Extended {
+		return boolValue, err
+	}
+
+	if containsFold(extendedTrue, value) {
+		return true, nil
+	}
+	if containsFold(extendedFalse, value) {
+		return false, nil
+	}
+	return false, &strconv.NumError{Func: "ParseBool", Num: value, Err: strconv.ErrSyntax}
+}
+
+func (o Options) acceptsPresence(spec reflect.Type) bool {
+	return o.Bool.Presence && indirect(spec).Kind() == reflect.Bool
+}
+
+func (o Options) withBoolTag(tag string) Options {
+	for _, mode := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(mode) {
+		case "extended":
+			o.Bool.Extended = true
+		case "presence":
+			o.Bool.Presence = true
+		case "strict":
+			o.Bool = BoolOptions{}
+		}
+	}
+	return o
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
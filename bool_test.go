@@ -0,0 +1,111 @@
+package envconfig
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestBoolExtended(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Debug    bool
+		Pointer  *bool
+		Flags    []bool
+		Indexed  []bool
+		Features map[string]bool
+	}
+	options := DefaultOptions()
+	options.Bool.Extended = true
+	options.Source = MapSource{
+		"DEBUG":          "Yes",
+		"POINTER":        "off",
+		"FLAGS":          "on,no,enabled,1",
+		"INDEXED_1":      "disabled",
+		"FEATURES_cache": "enabled",
+		"FEATURES_trace": "n",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, true, spec.Debug)
+	assert.Equal(t, false, *spec.Pointer)
+	assert.Equal(t, []bool{true, false, true, true}, spec.Flags)
+	assert.Equal(t, []bool{false, false}, spec.Indexed)
+	assert.Equal(t, map[string]bool{"cache": true, "trace": false}, spec.Features)
+
+	options.Source = MapSource{"DEBUG": "maybe"}
+	err := InitWithOptions(&TestSpec{}, options)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	assert.EqualError(t, err, `DEBUG: strconv.ParseBool: parsing "maybe": invalid syntax`)
+}
+
+func TestBoolStrictByDefault(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Debug bool
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"DEBUG": "yes"}
+
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+}
+
+func TestBoolPresence(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Debug    bool
+		Verbose  *bool
+		Quiet    bool
+		Name     string
+		Features map[string]bool
+	}
+	options := DefaultOptions()
+	options.Bool.Presence = true
+	options.Source = MapSource{"DEBUG": "", "VERBOSE": "", "NAME": "", "FEATURES_cache": ""}
+
+	spec := TestSpec{Name: "default"}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, true, spec.Debug)
+	assert.Equal(t, true, *spec.Verbose)
+	assert.Equal(t, false, spec.Quiet)
+	assert.Equal(t, "default", spec.Name)
+	assert.Equal(t, map[string]bool{"cache": true}, spec.Features)
+
+	options.Bool.Presence = false
+	options.Source = MapSource{"DEBUG": "", "VERBOSE": "", "NAME": ""}
+	spec = TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, false, spec.Debug)
+	assert.Nil(t, spec.Verbose)
+
+	options.Source = MapSource{"FEATURES_cache": ""}
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+}
+
+func TestBoolFieldTags(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Extended bool `bool:"extended"`
+		Present  bool `bool:"presence"`
+		Strict   bool `bool:"strict"`
+		Custom   bool `truthy:"ja, si" falsy:"nein"`
+		Disabled bool `falsy:"nein"`
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"EXTENDED": "on", "PRESENT": "", "CUSTOM": "JA", "DISABLED": "nein"}
+
+	spec := TestSpec{Disabled: true}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, TestSpec{Extended: true, Present: true, Custom: true}, spec)
+
+	options.Bool.Extended = true
+	options.Source = MapSource{"STRICT": "yes"}
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+}
@@ -0,0 +1,70 @@
+package envconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+const (
+	EncodingRaw        = "raw"
+	EncodingBase64     = "base64"
+	EncodingBase64URL  = "base64url"
+	EncodingHex        = "hex"
+	EncodingGzipBase64 = "gzip+base64"
+)
+
+func isBytes(spec reflect.Type) bool {
+	return spec.Kind() == reflect.Slice && spec.Elem().Kind() == reflect.Uint8
+}
+
+func decodeBytes(encoding, value string) ([]byte, error) {
+	if inner, ok := strings.CutPrefix(encoding, "gzip+"); ok {
+		compressed, err := decodeBytes(inner, value)
+		if err != nil {
+			return nil, err
+		}
+
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip data: %w", err)
+		}
+		defer reader.Close()
+
+		return io.ReadAll(reader)
+	}
+
+	switch encoding {
+	case "", EncodingRaw:
+		return []byte(value), nil
+	case EncodingBase64:
+		return decodeBase64(base64.StdEncoding, value)
+	case EncodingBase64URL:
+		return decodeBase64(base64.URLEncoding, value)
+	case EncodingHex:
+		decoded, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex data: %w", err)
+		}
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding: %q", encoding)
+	}
+}
+
+func decodeBase64(encoding *base64.Encoding, value string) ([]byte, error) {
+	if !strings.HasSuffix(strings.TrimSpace(value), "=") {
+		encoding = encoding.WithPadding(base64.NoPadding)
+	}
+
+	decoded, err := encoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 data: %w", err)
+	}
+	return decoded, nil
+}
@@ -0,0 +1,89 @@
+package envconfig
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func gzipBase64(t *testing.T, content string) string {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write([]byte(content))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return base64.StdEncoding.EncodeToString(buffer.Bytes())
+}
+
+func TestBytesField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Raw        []byte
+		Std        []byte `encoding:"base64"`
+		Unpadded   []byte `encoding:"base64"`
+		URL        []byte `encoding:"base64url"`
+		Hex        []byte `encoding:"hex"`
+		Compressed []byte `encoding:"gzip+base64"`
+		Keys       [][]byte
+		Templated  map[string][]byte `encoding:"hex"`
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"RAW":            "abc",
+		"STD":            "aGVsbG8=",
+		"UNPADDED":       "aGVsbG8",
+		"URL":            "-_8=",
+		"HEX":            "deadbeef",
+		"COMPRESSED":     gzipBase64(t, "certificate"),
+		"KEYS":           "a,b",
+		"TEMPLATED_SIGN": "0102",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []byte("abc"), spec.Raw)
+	assert.Equal(t, []byte("hello"), spec.Std)
+	assert.Equal(t, []byte("hello"), spec.Unpadded)
+	assert.Equal(t, []byte{0xfb, 0xff}, spec.URL)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, spec.Hex)
+	assert.Equal(t, []byte("certificate"), spec.Compressed)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, spec.Keys)
+	assert.Equal(t, map[string][]byte{"SIGN": {1, 2}}, spec.Templated)
+}
+
+func TestBytesDefaultEncoding(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Key   []byte
+		Plain []byte `encoding:"raw"`
+	}
+	options := DefaultOptions()
+	options.ByteEncoding = EncodingBase64
+	options.Source = MapSource{"KEY": "aGVsbG8=", "PLAIN": "aGVsbG8="}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []byte("hello"), spec.Key)
+	assert.Equal(t, []byte("aGVsbG8="), spec.Plain)
+}
+
+func TestBytesDecodeError(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Key []byte `encoding:"hex"`
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"KEY": "xyz"}
+
+	err := InitWithOptions(&TestSpec{}, options)
+	var variableErr *VariableError
+	assert.True(t, errors.As(err, &variableErr))
+	assert.Equal(t, "KEY", variableErr.Name)
+}
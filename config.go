@@ -0,0 +1,277 @@
+package envconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ConfigFormat int
+
+const (
+	FormatJSON ConfigFormat = iota
+	FormatTOML
+	FormatYAML
+)
+
+func (f ConfigFormat) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatTOML:
+		return "toml"
+	case FormatYAML:
+		return "yaml"
+	default:
+		return fmt.Sprintf("ConfigFormat(%d)", int(f))
+	}
+}
+
+func ConfigFormatFromPath(path string) (ConfigFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	default:
+		return 0, fmt.Errorf("unknown configuration format: %q", path)
+	}
+}
+
+type ConfigError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+type UnknownKeysError struct {
+	Keys []string
+}
+
+func (e *UnknownKeysError) Error() string {
+	return fmt.Sprintf("unknown configuration keys: %s", strings.Join(e.Keys, ", "))
+}
+
+func InitFromConfigFile(spec any, path string, options Options) error {
+	format, err := ConfigFormatFromPath(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = InitFromConfig(spec, file, format, options)
+	var configErr *ConfigError
+	if errors.As(err, &configErr) {
+		configErr.Path = path
+	}
+	return err
+}
+
+func InitFromConfig(spec any, r io.Reader, format ConfigFormat, options Options) error {
+	target := reflect.ValueOf(spec)
+
+	if target.Kind() != reflect.Pointer {
+		return ErrInvalidSpecification
+	}
+
+	document, err := parseConfig(r, format)
+	if err != nil {
+		return err
+	}
+
+	paths := options.collectPaths(target.Type())
+	unknown := make([]string, 0)
+
+	err = walkConfig(document, nil, options.Slice.FirstIndex, func(keys []string, value string) error {
+		matched := false
+		for _, path := range paths {
+			tokens, ok := path.match(keys, options.MatchCase)
+			if !ok {
+				continue
+			}
+
+			matched = true
+			if err := path.set(target, append(tokens, value)...); err != nil {
+				return &VariableError{Name: strings.Join(keys, "."), Err: err}
+			}
+		}
+
+		if !matched {
+			unknown = append(unknown, strings.Join(keys, "."))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(unknown) > 0 {
+		return &UnknownKeysError{Keys: unknown}
+	}
+	return nil
+}
+
+func parseConfig(r io.Reader, format ConfigFormat) (any, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		return parseJSONConfig(input)
+	case FormatTOML:
+		parser := tomlParser{input: string(input)}
+		return parser.parse()
+	case FormatYAML:
+		parser := newYAMLParser(string(input))
+		return parser.parse()
+	default:
+		return nil, fmt.Errorf("unknown configuration format: %s", format)
+	}
+}
+
+func parseJSONConfig(input []byte) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(string(input)))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, &ConfigError{Line: lineAt(string(input), int(syntaxErr.Offset)), Err: err}
+		}
+		return nil, err
+	}
+	return document, nil
+}
+
+func lineAt(input string, offset int) int {
+	if offset > len(input) {
+		offset = len(input)
+	}
+	return strings.Count(input[:offset], "\n") + 1
+}
+
+func walkConfig(node any, keys []string, firstIndex int, visit func([]string, string) error) error {
+	switch node := node.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		names := make([]string, 0, len(node))
+		for name := range node {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := walkConfig(node[name], append(keys[:len(keys):len(keys)], name), firstIndex, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		for index, element := range node {
+			key := strconv.Itoa(index + firstIndex)
+			if err := walkConfig(element, append(keys[:len(keys):len(keys)], key), firstIndex, visit); err != nil {
+				return err
+			}
+		}
+		return nil
+	case string:
+		return visit(keys, node)
+	case json.Number:
+		return visit(keys, node.String())
+	case bool:
+		return visit(keys, strconv.FormatBool(node))
+	default:
+		return visit(keys, fmt.Sprint(node))
+	}
+}
+
+type configPath struct {
+	segments []fragment
+	patterns []*regexp.Regexp
+	set      setterFunc
+}
+
+func (o Options) collectPaths(spec reflect.Type) []configPath {
+	paths := make([]configPath, 0)
+
+	o.analyze(spec, func(setter setterFunc, fragments ...fragment) {
+		path := configPath{set: setter}
+		for i := len(fragments) - 1; i >= 0; i-- {
+			f := fragments[i]
+			var pattern *regexp.Regexp
+			if f.dynamic {
+				expression := "^(?:" + f.pattern + ")$"
+				if !o.MatchCase {
+					expression = "(?i)" + expression
+				}
+				pattern = regexp.MustCompile(expression)
+			}
+			path.segments = append(path.segments, f)
+			path.patterns = append(path.patterns, pattern)
+		}
+		paths = append(paths, path)
+	})
+
+	return paths
+}
+
+func (p configPath) match(keys []string, matchCase bool) ([]string, bool) {
+	if len(keys) != len(p.segments) {
+		return nil, false
+	}
+
+	tokens := make([]string, 0)
+	for i, key := range keys {
+		if p.segments[i].dynamic {
+			submatches := p.patterns[i].FindStringSubmatch(key)
+			if submatches == nil {
+				return nil, false
+			}
+			tokens = append(tokens, submatches[1:]...)
+		} else if !matchName(p.segments[i].pattern, key, matchCase) {
+			return nil, false
+		}
+	}
+	return tokens, true
+}
+
+func matchName(name, key string, matchCase bool) bool {
+	if matchCase {
+		return name == key
+	}
+	return strings.EqualFold(name, key) || strings.EqualFold(name, normalizeKey(key))
+}
+
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
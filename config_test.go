@@ -0,0 +1,255 @@
+package envconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+type configEndpoint struct {
+	Host string
+	Port int
+}
+
+type configSpec struct {
+	Name      string
+	DB        configEndpoint
+	Replicas  []configEndpoint
+	Tags      []string
+	Labels    map[string]string
+	Weights   map[string]int
+	Debug     *bool
+	MaxConns  int
+	Timeout   float64
+	Multiline string
+}
+
+func assertConfigSpec(t *testing.T, spec configSpec) {
+	assert.Equal(t, "app", spec.Name)
+	assert.Equal(t, configEndpoint{Host: "db.local", Port: 5432}, spec.DB)
+	assert.Equal(t, []configEndpoint{{Host: "r1", Port: 1}, {Host: "r2", Port: 2}}, spec.Replicas)
+	assert.Equal(t, []string{"a", "b"}, spec.Tags)
+	assert.Equal(t, map[string]string{"team": "core"}, spec.Labels)
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, spec.Weights)
+	assert.Equal(t, true, *spec.Debug)
+	assert.Equal(t, 10, spec.MaxConns)
+	assert.Equal(t, 1.5, spec.Timeout)
+	assert.Equal(t, "line one\nline two\n", spec.Multiline)
+}
+
+func TestInitFromJSONConfig(t *testing.T) {
+	input := `{
+		"name": "app",
+		"db": {"host": "db.local", "port": 5432},
+		"replicas": [{"host": "r1", "port": 1}, {"host": "r2", "port": 2}],
+		"tags": ["a", "b"],
+		"labels": {"team": "core"},
+		"weights": {"x": 1, "y": 2},
+		"debug": true,
+		"max_conns": 10,
+		"timeout": 1.5,
+		"multiline": "line one\nline two\n",
+		"unused": null
+	}`
+
+	spec := configSpec{}
+	assert.NoError(t, InitFromConfig(&spec, strings.NewReader(input), FormatJSON, DefaultOptions()))
+	assertConfigSpec(t, spec)
+}
+
+func TestInitFromTOMLConfig(t *testing.T) {
+	input := `
+# comment
+name = "app"
+debug = true
+max_conns = 10
+timeout = 1.5
+tags = [
+  "a", # first
+  'b',
+]
+multiline = """
+line one
+line two
+"""
+
+[db]
+host = "db.local"
+port = 5432
+
+[labels]
+team = "core"
+
+[[replicas]]
+host = "r1"
+port = 1
+
+[[replicas]]
+host = "r2"
+port = 2
+
+[weights]
+x = 1
+"y" = 2
+`
+
+	spec := configSpec{}
+	assert.NoError(t, InitFromConfig(&spec, strings.NewReader(input), FormatTOML, DefaultOptions()))
+	assertConfigSpec(t, spec)
+}
+
+func TestInitFromYAMLConfig(t *testing.T) {
+	input := `---
+# comment
+name: app
+db:
+  host: db.local   # trailing comment
+  port: 5432
+replicas:
+  - host: r1
+    port: 1
+  - host: "r2"
+    port: 2
+tags: [a, 'b']
+labels:
+  team: core
+weights: {x: 1, y: 2}
+debug: true
+max_conns: 10
+timeout: 1.5
+multiline: |
+  line one
+  line two
+`
+
+	spec := configSpec{}
+	assert.NoError(t, InitFromConfig(&spec, strings.NewReader(input), FormatYAML, DefaultOptions()))
+	assertConfigSpec(t, spec)
+}
+
+func TestInitFromYAMLConfigSequenceAtKeyIndent(t *testing.T) {
+	type TestSpec struct {
+		Tags []string
+		Name string
+	}
+	input := "tags:\n- a\n- b\nname: app\n"
+
+	spec := TestSpec{}
+	assert.NoError(t, InitFromConfig(&spec, strings.NewReader(input), FormatYAML, DefaultOptions()))
+	assert.Equal(t, []string{"a", "b"}, spec.Tags)
+	assert.Equal(t, "app", spec.Name)
+}
+
+func TestInitFromConfigInlineValues(t *testing.T) {
+	type TestSpec struct {
+		Tags   []string
+		Labels map[string]string
+	}
+	input := `{"tags": "a,b", "labels": "team:core"}`
+
+	spec := TestSpec{}
+	assert.NoError(t, InitFromConfig(&spec, strings.NewReader(input), FormatJSON, DefaultOptions()))
+	assert.Equal(t, []string{"a", "b"}, spec.Tags)
+	assert.Equal(t, map[string]string{"team": "core"}, spec.Labels)
+}
+
+func TestInitFromConfigMatchCase(t *testing.T) {
+	type TestSpec struct {
+		Name string
+	}
+	options := DefaultOptions()
+	options.MatchCase = true
+
+	spec := TestSpec{}
+	err := InitFromConfig(&spec, strings.NewReader(`{"name": "app"}`), FormatJSON, options)
+	var unknownErr *UnknownKeysError
+	assert.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, "", spec.Name)
+
+	spec = TestSpec{}
+	assert.NoError(t, InitFromConfig(&spec, strings.NewReader(`{"Name": "app"}`), FormatJSON, options))
+	assert.Equal(t, "app", spec.Name)
+}
+
+func TestInitFromConfigUnknownKeys(t *testing.T) {
+	type TestSpec struct {
+		Name string
+	}
+	input := `{"name": "app", "extra": {"nested": 1}, "other": [1]}`
+
+	spec := TestSpec{}
+	err := InitFromConfig(&spec, strings.NewReader(input), FormatJSON, DefaultOptions())
+	var unknownErr *UnknownKeysError
+	assert.True(t, errors.As(err, &unknownErr))
+	assert.Equal(t, []string{"extra.nested", "other.0"}, unknownErr.Keys)
+	assert.Equal(t, "app", spec.Name)
+}
+
+func TestInitFromConfigInvalidValue(t *testing.T) {
+	type TestSpec struct {
+		DB configEndpoint
+	}
+
+	spec := TestSpec{}
+	err := InitFromConfig(&spec, strings.NewReader("db:\n  port: abc\n"), FormatYAML, DefaultOptions())
+	var variableErr *VariableError
+	assert.True(t, errors.As(err, &variableErr))
+	assert.Equal(t, "db.port", variableErr.Name)
+}
+
+func TestInitFromConfigSyntaxErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format ConfigFormat
+		input  string
+		line   int
+	}{
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			input:  "{\n\"a\": 1,\n\"b\" 2\n}",
+			line:   3,
+		},
+		{
+			name:   "TOML",
+			format: FormatTOML,
+			input:  "a = 1\n\nb 2\n",
+			line:   3,
+		},
+		{
+			name:   "YAML",
+			format: FormatYAML,
+			input:  "a: 1\nb:\n  c: 2\n    d: 3\n",
+			line:   4,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			spec := configSpec{}
+			err := InitFromConfig(&spec, strings.NewReader(testCase.input), testCase.format, DefaultOptions())
+			var configErr *ConfigError
+			assert.True(t, errors.As(err, &configErr))
+			assert.Equal(t, testCase.line, configErr.Line)
+		})
+	}
+}
+
+func TestInitFromConfigFile(t *testing.T) {
+	type TestSpec struct {
+		Name string
+	}
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(path, []byte("name: app\n"), 0o644))
+
+	spec := TestSpec{}
+	assert.NoError(t, InitFromConfigFile(&spec, path, DefaultOptions()))
+	assert.Equal(t, "app", spec.Name)
+
+	_, err := ConfigFormatFromPath("config.ini")
+	assert.Error(t, err)
+}
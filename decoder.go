@@ -0,0 +1,48 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type DecoderFunc func(string) (any, error)
+
+func RegisterDecoder[T any](options *Options, decode func(string) (T, error)) {
+	if options.Decoders == nil {
+		options.Decoders = make(map[reflect.Type]DecoderFunc)
+	}
+
+	options.Decoders[reflect.TypeOf((*T)(nil)).Elem()] = func(value string) (any, error) {
+		return decode(value)
+	}
+}
+
+func (o Options) decoder(spec reflect.Type) (DecoderFunc, bool) {
+	if decode, ok := o.Decoders[spec]; ok {
+		return decode, true
+	}
+	decode, ok := builtinDecoders[spec]
+	return decode, ok
+}
+
+func setDecoded(target reflect.Value, decode DecoderFunc, value string) error {
+	decoded, err := decode(value)
+	if err != nil {
+		return err
+	}
+
+	if decoded == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	result := reflect.ValueOf(decoded)
+	if !result.Type().AssignableTo(target.Type()) {
+		if result.Kind() != target.Kind() || !result.Type().ConvertibleTo(target.Type()) {
+			return fmt.Errorf("decoder for %s returned %s", target.Type(), result.Type())
+		}
+		result = result.Convert(target.Type())
+	}
+	target.Set(result)
+	return nil
+}
@@ -0,0 +1,179 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/assert"
+)
+
+type decimal struct {
+	Units int64
+	Cents int64
+}
+
+func parseDecimal(value string) (decimal, error) {
+	units, cents, _ := strings.Cut(value, ".")
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return decimal{}, err
+	}
+	c, err := strconv.ParseInt(cents+"00"[len(cents):], 10, 64)
+	if err != nil {
+		return decimal{}, err
+	}
+	return decimal{u, c}, nil
+}
+
+type color int
+
+const (
+	red color = iota + 1
+	green
+)
+
+func parseColor(value string) (color, error) {
+	switch value {
+	case "red":
+		return red, nil
+	case "green":
+		return green, nil
+	default:
+		return 0, fmt.Errorf("unknown color %q", value)
+	}
+}
+
+func TestDecoderField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Price   decimal
+		Pointer *decimal
+		Color   color
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"PRICE":       "12.5",
+		"PRICE_UNITS": "99",
+		"POINTER":     "1.25",
+		"COLOR":       "green",
+	}
+	RegisterDecoder(&options, parseDecimal)
+	RegisterDecoder(&options, parseColor)
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, decimal{12, 50}, spec.Price)
+	assert.Equal(t, decimal{1, 25}, *spec.Pointer)
+	assert.Equal(t, green, spec.Color)
+}
+
+func TestDecoderCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Prices  []decimal
+		Palette map[string]color
+		ByColor map[color]decimal
+		Indexed []color
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"PRICES":       "1.1, 2.2",
+		"PALETTE":      "bg:red,fg:green",
+		"BY_COLOR_red": "3.3",
+		"INDEXED_1":    "red",
+	}
+	RegisterDecoder(&options, parseDecimal)
+	RegisterDecoder(&options, parseColor)
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []decimal{{1, 10}, {2, 20}}, spec.Prices)
+	assert.Equal(t, map[string]color{"bg": red, "fg": green}, spec.Palette)
+	assert.Equal(t, map[color]decimal{red: {3, 30}}, spec.ByColor)
+	assert.Equal(t, []color{0, red}, spec.Indexed)
+}
+
+func TestDecoderOverridesBuiltin(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Timeout time.Duration
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TIMEOUT": "5"}
+	options.Decoders = map[reflect.Type]DecoderFunc{
+		reflect.TypeOf(time.Duration(0)): func(value string) (any, error) {
+			seconds, err := strconv.Atoi(value)
+			return time.Duration(seconds) * time.Second, err
+		},
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, 5*time.Second, spec.Timeout)
+}
+
+func TestDecoderError(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Color color
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"COLOR": "blue"}
+	RegisterDecoder(&options, parseColor)
+
+	err := InitWithOptions(&TestSpec{}, options)
+	var variableErr *VariableError
+	assert.True(t, errors.As(err, &variableErr))
+	assert.EqualError(t, err, `COLOR: unknown color "blue"`)
+}
+
+func TestDecoderWrongType(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Color color
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"COLOR": "red"}
+	options.Decoders = map[reflect.Type]DecoderFunc{
+		reflect.TypeOf(color(0)): func(value string) (any, error) {
+			return value, nil
+		},
+	}
+
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), "COLOR: decoder for envconfig.color returned string")
+}
+
+func TestDecoderLossyConversion(t *testing.T) {
+	t.Parallel()
+
+	type identifier string
+	type TestSpec struct {
+		ID identifier
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"ID": "a"}
+	options.Decoders = map[reflect.Type]DecoderFunc{
+		reflect.TypeOf(identifier("")): func(value string) (any, error) {
+			return 65, nil
+		},
+	}
+
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), "ID: decoder for envconfig.identifier returned int")
+
+	options.Decoders[reflect.TypeOf(identifier(""))] = func(value string) (any, error) {
+		return "id-" + value, nil
+	}
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, identifier("id-a"), spec.ID)
+}
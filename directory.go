@@ -0,0 +1,48 @@
+package envconfig
+
+import (
+	"io/fs"
+	"os"
+	"strings"
+)
+
+const CredentialsDirectory = "CREDENTIALS_DIRECTORY"
+
+func LoadDirectory(fsys fs.FS) (MapSource, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make(MapSource)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, "..") {
+			continue
+		}
+
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		variables[name] = strings.TrimRight(string(content), "\r\n")
+	}
+
+	return variables, nil
+}
+
+func LoadCredentials() (MapSource, error) {
+	dir := os.Getenv(CredentialsDirectory)
+	if dir == "" {
+		return MapSource{}, nil
+	}
+	return LoadDirectory(os.DirFS(dir))
+}
@@ -0,0 +1,58 @@
+package envconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestLoadDirectory(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+		MapField  map[string]string
+	}
+
+	source, err := LoadDirectory(fstest.MapFS{
+		"TEST_FIELD":             {Data: []byte("test\n")},
+		"MAP_FIELD_MAP_KEY":      {Data: []byte("entry")},
+		"..data/TEST_FIELD":      {Data: []byte("ignored")},
+		"nested/MAP_FIELD_OTHER": {Data: []byte("ignored")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, MapSource{
+		"TEST_FIELD":        "test",
+		"MAP_FIELD_MAP_KEY": "entry",
+	}, source)
+
+	options := DefaultOptions()
+	options.Source = source
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "test", spec.TestField)
+	assert.Equal(t, map[string]string{"MAP_KEY": "entry"}, spec.MapField)
+}
+
+func TestLoadCredentials(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "TEST_FIELD"), []byte("secret\n"), 0o600))
+
+	withEnv(CredentialsDirectory, dir, func() {
+		source, err := LoadCredentials()
+		assert.NoError(t, err)
+		assert.Equal(t, MapSource{"TEST_FIELD": "secret"}, source)
+	})
+}
+
+func TestLoadCredentialsUnset(t *testing.T) {
+	withEnv(CredentialsDirectory, "", func() {
+		source, err := LoadCredentials()
+		assert.NoError(t, err)
+		assert.Len(t, source, 0)
+	})
+}
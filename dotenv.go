@@ -0,0 +1,280 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type DotenvError struct {
+	Path string
+	Line int
+	Err  error
+}
+
+func (e *DotenvError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *DotenvError) Unwrap() error {
+	return e.Err
+}
+
+func ParseDotenv(r io.Reader) (MapSource, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := dotenvParser{input: string(input), line: 1}
+	return parser.parse()
+}
+
+func LoadDotenv(path string) (MapSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	source, err := ParseDotenv(file)
+	if err != nil {
+		var dotenvErr *DotenvError
+		if errors.As(err, &dotenvErr) {
+			dotenvErr.Path = path
+		}
+		return nil, err
+	}
+	return source, nil
+}
+
+func FindDotenv(dir, name string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+		}
+		dir = parent
+	}
+}
+
+type dotenvParser struct {
+	input string
+	pos   int
+	line  int
+}
+
+func (p *dotenvParser) parse() (MapSource, error) {
+	variables := make(MapSource)
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return variables, nil
+		}
+
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+
+		line := p.line
+		key := p.readKey()
+		if key == "export" && p.isSpace() {
+			p.skipSpaces()
+			key = p.readKey()
+		}
+		if !isDotenvKey(key) {
+			return nil, &DotenvError{Line: line, Err: fmt.Errorf("invalid variable name %q", key)}
+		}
+
+		p.skipSpaces()
+		if p.eof() || p.peek() != '=' {
+			return nil, &DotenvError{Line: line, Err: fmt.Errorf("expected '=' after %q", key)}
+		}
+		p.pos++
+		p.skipSpaces()
+
+		value, err := p.readValue()
+		if err != nil {
+			return nil, &DotenvError{Line: line, Err: err}
+		}
+
+		p.skipSpaces()
+		if !p.eof() && p.peek() == '#' {
+			p.skipLine()
+		} else if !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+			return nil, &DotenvError{Line: p.line, Err: fmt.Errorf("unexpected character %q after value of %q", p.peek(), key)}
+		}
+
+		variables[key] = value
+	}
+}
+
+func (p *dotenvParser) readKey() string {
+	start := p.pos
+	for !p.eof() && p.peek() != '=' && p.peek() != '\n' && p.peek() != '\r' && !p.isSpace() {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *dotenvParser) readValue() (string, error) {
+	if p.eof() {
+		return "", nil
+	}
+
+	switch p.peek() {
+	case '\'':
+		return p.readSingleQuoted()
+	case '"':
+		return p.readDoubleQuoted()
+	default:
+		return p.readUnquoted(), nil
+	}
+}
+
+func (p *dotenvParser) readSingleQuoted() (string, error) {
+	p.pos++
+	start := p.pos
+	for !p.eof() {
+		switch p.peek() {
+		case '\'':
+			value := p.input[start:p.pos]
+			p.pos++
+			return value, nil
+		case '\n':
+			p.line++
+		}
+		p.pos++
+	}
+	return "", errors.New("unterminated single-quoted value")
+}
+
+func (p *dotenvParser) readDoubleQuoted() (string, error) {
+	p.pos++
+	var value strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return value.String(), nil
+		case '\\':
+			p.pos++
+			if p.eof() {
+				return "", errors.New("unterminated double-quoted value")
+			}
+			switch escaped := p.peek(); escaped {
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case '"', '\\', '$', '`':
+				value.WriteByte(escaped)
+			case '\n':
+				p.line++
+			default:
+				value.WriteByte('\\')
+				value.WriteByte(escaped)
+			}
+		case '\n':
+			p.line++
+			value.WriteByte(c)
+		default:
+			value.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", errors.New("unterminated double-quoted value")
+}
+
+func (p *dotenvParser) readUnquoted() string {
+	start := p.pos
+	for !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+		if p.peek() == '#' && p.pos > 0 && isBlank(p.input[p.pos-1]) {
+			break
+		}
+		p.pos++
+	}
+	return strings.TrimSpace(p.input[start:p.pos])
+}
+
+func (p *dotenvParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case '\n':
+			p.line++
+		case ' ', '\t', '\r':
+		default:
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipSpaces() {
+	for p.isSpace() {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) isSpace() bool {
+	return !p.eof() && isBlank(p.peek())
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isDotenvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '.' || c == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package envconfig
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestParseDotenv(t *testing.T) {
+	input := `
+# comment
+PLAIN=value
+SPACED = spaced value  # trailing comment
+export EXPORTED=exported
+SINGLE='single # not a comment \n'
+DOUBLE="double \"quoted\"\tvalue"
+HASH=a#b
+EMPTY=
+COMMENTED= # comment only
+LEADING=#value
+MULTI="first
+second"
+`
+	source, err := ParseDotenv(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, MapSource{
+		"PLAIN":     "value",
+		"SPACED":    "spaced value",
+		"EXPORTED":  "exported",
+		"SINGLE":    `single # not a comment \n`,
+		"DOUBLE":    "double \"quoted\"\tvalue",
+		"HASH":      "a#b",
+		"EMPTY":     "",
+		"COMMENTED": "",
+		"LEADING":   "#value",
+		"MULTI":     "first\nsecond",
+	}, source)
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{
+			name:  "MissingSeparator",
+			input: "A=1\nINVALID\n",
+			line:  2,
+		},
+		{
+			name:  "InvalidName",
+			input: "A=1\n\n1A=2\n",
+			line:  3,
+		},
+		{
+			name:  "UnterminatedQuote",
+			input: "A=1\nB=\"open\n\nC=3\n",
+			line:  2,
+		},
+		{
+			name:  "TrailingGarbage",
+			input: "A='quoted' garbage\n",
+			line:  1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := ParseDotenv(strings.NewReader(testCase.input))
+			var dotenvErr *DotenvError
+			assert.True(t, errors.As(err, &dotenvErr))
+			assert.Equal(t, testCase.line, dotenvErr.Line)
+		})
+	}
+}
+
+func TestDotenvAsSource(t *testing.T) {
+	type ChildSpec struct {
+		TestField string
+	}
+	type TestSpec struct {
+		TestField  int
+		ChildField ChildSpec
+		MapField   map[string]string
+	}
+
+	source, err := ParseDotenv(strings.NewReader("TEST_FIELD=5\nCHILD_FIELD_TEST_FIELD=child\nMAP_FIELD_MAP_KEY=entry\n"))
+	assert.NoError(t, err)
+
+	options := DefaultOptions()
+	options.Source = source
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, 5, spec.TestField)
+	assert.Equal(t, "child", spec.ChildField.TestField)
+	assert.Equal(t, "entry", spec.MapField["MAP_KEY"])
+}
+
+func TestFindDotenv(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	assert.NoError(t, os.MkdirAll(nested, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, ".env"), []byte("A=1\n"), 0o644))
+
+	path, err := FindDotenv(nested, ".env")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, ".env"), path)
+
+	_, err = FindDotenv(nested, ".missing")
+	assert.True(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestLoadDotenvReportsPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(path, []byte("A=1\nB\n"), 0o644))
+
+	_, err := LoadDotenv(path)
+	assert.EqualError(t, err, path+":2: expected '=' after \"B\"")
+}
@@ -0,0 +1,44 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type EnumError struct {
+	Value   string
+	Allowed []string
+}
+
+func (e *EnumError) Error() string {
+	return fmt.Sprintf("invalid value %q: must be one of %s", e.Value, strings.Join(e.Allowed, ", "))
+}
+
+func RegisterEnum[T any](options *Options, values ...string) {
+	if options.Enums == nil {
+		options.Enums = make(map[reflect.Type][]string)
+	}
+	options.Enums[reflect.TypeOf((*T)(nil)).Elem()] = values
+}
+
+func (o Options) allowedValues(spec reflect.Type) []string {
+	if o.enum != nil {
+		return o.enum
+	}
+	return o.Enums[indirect(spec)]
+}
+
+func (o Options) checkEnum(spec reflect.Type, value string) (string, error) {
+	allowed := o.allowedValues(spec)
+	if allowed == nil {
+		return value, nil
+	}
+
+	for _, candidate := range allowed {
+		if candidate == value || o.EnumIgnoreCase && strings.EqualFold(candidate, value) {
+			return candidate, nil
+		}
+	}
+	return "", &EnumError{Value: value, Allowed: allowed}
+}
@@ -0,0 +1,91 @@
+package envconfig
+
+import (
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+type logLevel string
+
+func TestEnumFieldTag(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Mode  string            `enum:"dev, prod"`
+		Modes []string          `enum:"dev,prod"`
+		Roles map[string]string `enum:"reader,writer"`
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"MODE": "prod", "MODES": "dev,prod", "ROLES_alice": "writer"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "prod", spec.Mode)
+	assert.Equal(t, []string{"dev", "prod"}, spec.Modes)
+	assert.Equal(t, map[string]string{"alice": "writer"}, spec.Roles)
+
+	options.Source = MapSource{"MODE": "staging"}
+	err := InitWithOptions(&TestSpec{}, options)
+	var enumErr *EnumError
+	assert.True(t, errors.As(err, &enumErr))
+	assert.Equal(t, []string{"dev", "prod"}, enumErr.Allowed)
+	assert.EqualError(t, err, `MODE: invalid value "staging": must be one of dev, prod`)
+}
+
+func TestEnumRegisteredType(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Level     logLevel
+		Pointer   *logLevel
+		Overrides map[string]logLevel
+		Custom    logLevel `enum:"trace"`
+	}
+	options := DefaultOptions()
+	RegisterEnum[logLevel](&options, "debug", "info", "warn")
+	options.Source = MapSource{"LEVEL": "info", "POINTER": "warn", "OVERRIDES_HTTP": "debug", "CUSTOM": "trace"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, logLevel("info"), spec.Level)
+	assert.Equal(t, logLevel("warn"), *spec.Pointer)
+	assert.Equal(t, map[string]logLevel{"HTTP": "debug"}, spec.Overrides)
+	assert.Equal(t, logLevel("trace"), spec.Custom)
+
+	options.Source = MapSource{"OVERRIDES_HTTP": "verbose"}
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+}
+
+func TestEnumIgnoreCase(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Level logLevel
+	}
+	options := DefaultOptions()
+	RegisterEnum[logLevel](&options, "debug", "info")
+	options.Source = MapSource{"LEVEL": "INFO"}
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+
+	options.EnumIgnoreCase = true
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, logLevel("info"), spec.Level)
+}
+
+func TestEnumUsage(t *testing.T) {
+	type TestSpec struct {
+		Level logLevel `usage:"log level"`
+		Mode  string   `enum:"dev,prod"`
+	}
+	options := DefaultOptions()
+	RegisterEnum[logLevel](&options, "debug", "info")
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	assert.NoError(t, BindFlags(flags, &TestSpec{}, options))
+	assert.Equal(t, "log level (one of: debug, info)", flags.Lookup("level").Usage)
+	assert.Equal(t, "(one of: dev, prod)", flags.Lookup("mode").Usage)
+}
@@ -1,24 +1,42 @@
 package envconfig
 
 import (
+	"context"
+	"encoding"
 	"errors"
 	"fmt"
-	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var ErrInvalidSpecification = errors.New("specification must be a struct pointer or map")
 
+type VariableError struct {
+	Name string
+	Err  error
+}
+
+func (e *VariableError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Err)
+}
+
+func (e *VariableError) Unwrap() error {
+	return e.Err
+}
+
 type setterFunc func(target reflect.Value, tokens ...string) error
 
 var camelCase = regexp.MustCompile("[A-Z][^A-Z]*")
 
 type Variable[PATTERN any] struct {
-	pattern PATTERN
-	set     setterFunc
+	pattern  PATTERN
+	set      setterFunc
+	presence bool
+	leaf     int
 }
 
 func (v Variable[PATTERN]) String() string {
@@ -29,22 +47,72 @@ type Options struct {
 	Prefix    string
 	MatchCase bool
 
+	Source Source
+	Report func(name, origin string)
+	Expand bool
+
+	Resolvers map[string]Resolver
+
+	File  FileOptions
 	Map   MapOptions
 	Slice SliceOptions
+	Bool  BoolOptions
+
+	NumberLiterals bool
+	Quantities     bool
+	TimeLayout     string
+	ByteEncoding   string
+
+	Decoders map[reflect.Type]DecoderFunc
+
+	Enums          map[reflect.Type][]string
+	EnumIgnoreCase bool
+
+	GroupSeparators []string
 
 	Formatters []Formatter
+
+	enum []string
+	json bool
 }
 
+type FileOptions struct {
+	Enabled  bool
+	Suffix   string
+	Conflict FileConflict
+}
+
+type FileConflict int
+
+const (
+	FileConflictError FileConflict = iota
+	FilePreferVariable
+	FilePreferFile
+)
+
 type MapOptions struct {
 	KeyPattern        string
 	EntrySeparator    string
 	KeyValueSeparator string
+	Quoting           bool
+	JSON              JSONSyntax
+	RejectDuplicates  bool
 }
 
 type SliceOptions struct {
 	IndexPattern     string
 	ElementSeparator string
 	FirstIndex       int
+	MaxLength        int
+	Quoting          bool
+	JSON             JSONSyntax
+}
+
+type BoolOptions struct {
+	Extended bool
+	Presence bool
+	True     []string
+	False    []string
 }
 
 type SplitFunction func(string) []string
@@ -58,6 +126,10 @@ type Formatter struct {
 
 func DefaultOptions() Options {
 	return Options{
+		Source: EnvironmentSource(),
+		File: FileOptions{
+			Suffix: "_FILE",
+		},
 		Map: MapOptions{
 			KeyPattern:        "(.+)",
 			EntrySeparator:    ",",
@@ -66,7 +138,11 @@ func DefaultOptions() Options {
 		Slice: SliceOptions{
 			IndexPattern:     "([0-9]+)",
 			ElementSeparator: ",",
+			MaxLength:        1024,
 		},
+		TimeLayout:      time.RFC3339,
+		ByteEncoding:    EncodingRaw,
+		GroupSeparators: []string{";", "|"},
 		Formatters: []Formatter{
 			{
 				Split: func(name string) []string {
@@ -92,61 +168,158 @@ func Init(spec any) error {
 	return InitWithOptions(spec, DefaultOptions())
 }
 
+func InitFromMap(spec any, variables map[string]string) error {
+	options := DefaultOptions()
+	options.Source = MapSource(variables)
+	return InitWithOptions(spec, options)
+}
+
+func InitFromEnviron(spec any, environ []string) error {
+	options := DefaultOptions()
+	options.Source = EnvironSource(environ)
+	return InitWithOptions(spec, options)
+}
+
 func InitWithOptions(spec any, options Options) error {
+	return InitWithContext(context.Background(), spec, options)
+}
+
+func InitWithContext(ctx context.Context, spec any, options Options) error {
 	target := reflect.ValueOf(spec)
 
 	if target.Kind() != reflect.Pointer {
 		return ErrInvalidSpecification
 	}
 
+	source := options.Source
+	if source == nil {
+		source = EnvironmentSource()
+	}
+
 	variables, templates := options.collectVariables(target.Type())
+	assignments := make([]assignment, 0)
 
 	for _, variable := range variables {
-		value := os.Getenv(variable.pattern)
-		if value != "" {
-			err := variable.set(target, value)
-			if err != nil {
-				return err
-			}
+		fileName := ""
+		if options.File.Enabled {
+			fileName = variable.pattern + options.File.Suffix
+		}
+
+		value, key, err := options.lookup(source, variable.pattern, fileName)
+		if err == nil && options.Expand {
+			value, err = options.expand(source, variable.pattern, value)
+		}
+		if err == nil && options.Resolvers != nil {
+			value, err = options.resolve(ctx, source, variable.pattern, value)
+		}
+		if err != nil {
+			return err
+		}
+		if value != "" || variable.presence && options.present(source, variable.pattern, fileName) {
+			assignments = append(assignments, assignment{
+				name:   variable.pattern,
+				key:    key,
+				target: fmt.Sprint(variable.leaf),
+				tokens: []string{value},
+				set:    variable.set,
+			})
 		}
 	}
 
 	if len(templates) > 0 {
-		for key, value := range getEnvironment() {
+		names, files := options.templateCandidates(source.Keys())
+		for _, name := range names {
+			matches := make([]assignment, 0)
 			for _, template := range templates {
-				tokens := template.pattern.FindStringSubmatch(key)
+				tokens := template.pattern.FindStringSubmatch(name)
 				if len(tokens) > 0 {
-					tokens = append(tokens[1:], value)
-					err := template.set(target, tokens...)
-					if err != nil {
-						return err
-					}
+					matches = append(matches, assignment{
+						name:   name,
+						target: fmt.Sprint(template.leaf, tokens[1:]),
+						tokens: tokens[1:],
+						set:    template.set,
+					})
 				}
 			}
+			if len(matches) == 0 {
+				continue
+			}
+
+			value, key, err := options.lookup(source, name, files[name])
+			if err == nil && options.Expand {
+				value, err = options.expand(source, name, value)
+			}
+			if err == nil && options.Resolvers != nil {
+				value, err = options.resolve(ctx, source, name, value)
+			}
+			if err != nil {
+				return err
+			}
+			if _, ok := source.Lookup(name); !ok && value == "" {
+				continue
+			}
+
+			for _, match := range matches {
+				match.key = key
+				match.tokens = append(match.tokens, value)
+				assignments = append(assignments, match)
+			}
+		}
+	}
+
+	if ranked, ok := source.(rankedSource); ok {
+		sort.SliceStable(assignments, func(i, j int) bool {
+			return ranked.rank(assignments[i].key) < ranked.rank(assignments[j].key)
+		})
+	}
+
+	winners := make(map[string]int)
+	for i, assignment := range assignments {
+		err := assignment.set(target, assignment.tokens...)
+		if err != nil {
+			return &VariableError{Name: assignment.name, Err: err}
+		}
+		winners[assignment.target] = i
+	}
+
+	if options.Report != nil {
+		for i, assignment := range assignments {
+			if winners[assignment.target] != i {
+				continue
+			}
+
+			origin := ""
+			if origins, ok := source.(OriginSource); ok {
+				origin, _ = origins.Origin(assignment.key)
+			}
+			options.Report(assignment.name, origin)
 		}
 	}
 
 	return nil
 }
 
-func getEnvironment() map[string]string {
-	variables := make(map[string]string)
-	for _, variable := range os.Environ() {
-		tokens := strings.SplitN(variable, "=", 2)
-		variables[tokens[0]] = tokens[1]
-	}
-	return variables
+type assignment struct {
+	name   string
+	key    string
+	target string
+	tokens []string
+	set    setterFunc
 }
 
 func (o Options) collectVariables(spec reflect.Type) ([]Variable[string], []Variable[*regexp.Regexp]) {
 	variables := make([]Variable[string], 0)
 	templates := make([]Variable[*regexp.Regexp], 0)
 
+	leaf := 0
 	o.analyze(spec, func(setter setterFunc, fragments ...fragment) {
+		leaf++
 		if o.Prefix != "" {
-			fragments = append(fragments, fragment{o.Prefix, false})
+			fragments = append(fragments, fragment{o.Prefix, false, false})
 		}
 
+		presence := len(fragments) > 0 && fragments[0].presence
+
 		for _, formatter := range o.Formatters {
 			pattern, dynamic := format(formatter, fragments)
 			//fmt.Printf("Variable: %q (dynamic: %v)\n", name, dynamic)
@@ -159,11 +332,14 @@ func (o Options) collectVariables(spec reflect.Type) ([]Variable[string], []Vari
 				templates = append(templates, Variable[*regexp.Regexp]{
 					pattern: regexp.MustCompile(pattern),
 					set:     setter,
+					leaf:    leaf,
 				})
 			} else {
 				variables = append(variables, Variable[string]{
-					pattern: pattern,
-					set:     setter,
+					pattern:  pattern,
+					set:      setter,
+					presence: presence,
+					leaf:     leaf,
 				})
 			}
 		}
@@ -191,8 +367,9 @@ func format(formatter Formatter, fragments []fragment) (string, bool) {
 }
 
 type fragment struct {
-	pattern string
-	dynamic bool
+	pattern  string
+	dynamic  bool
+	presence bool
 }
 
 func (o Options) analyze(spec reflect.Type, collect func(setterFunc, ...fragment)) {
@@ -205,6 +382,8 @@ func (o Options) analyze(spec reflect.Type, collect func(setterFunc, ...fragment
 		o.analyzeMap(spec, collect)
 	case reflect.Slice:
 		o.analyzeSlice(spec, collect)
+	case reflect.Array:
+		o.analyzeArray(spec, collect)
 	default:
 		// do nothing
 	}
@@ -232,34 +411,29 @@ func (o Options) analyzeStruct(spec reflect.Type, collect func(setterFunc, ...fr
 		field := spec.Field(index)
 
 		if field.IsExported() {
-			if isPrimitive(field.Type) {
+			fieldOptions := o.withField(field)
+
+			if fieldOptions.isPrimitive(field.Type) {
 				setter := func(target reflect.Value, values ...string) error {
-					return setPrimitive(target.Field(index), values[0])
+					return fieldOptions.setPrimitive(target.Field(index), values[0])
 				}
-				collect(setter, fragment{field.Name, false})
+				collect(setter, fragment{field.Name, false, fieldOptions.acceptsPresence(field.Type)})
 			} else {
-				if isPrimitiveMap(field.Type) {
-					setter := func(target reflect.Value, values ...string) error {
-						return o.setPrimitiveMap(field.Type, target.Field(index), values[0])
-					}
-					collect(setter, fragment{field.Name, false})
-				}
-
-				if isPrimitiveSlice(field.Type) {
+				if fieldOptions.isPrimitiveMap(field.Type) || fieldOptions.isPrimitiveSlice(field.Type) {
 					setter := func(target reflect.Value, values ...string) error {
-						return o.setPrimitiveSlice(field.Type, target.Field(index), values[0])
+						return fieldOptions.setCollection(field.Type, target.Field(index), values[0])
 					}
-					collect(setter, fragment{field.Name, false})
+					collect(setter, fragment{field.Name, false, false})
 				}
 
-				o.analyze(field.Type, func(set setterFunc, fragments ...fragment) {
+				fieldOptions.analyze(field.Type, func(set setterFunc, fragments ...fragment) {
 					setter := func(target reflect.Value, values ...string) error {
 						return set(target.Field(index), values...)
 					}
 					if field.Anonymous {
 						collect(setter, fragments...)
 					}
-					collect(setter, append(fragments, fragment{field.Name, false})...)
+					collect(setter, append(fragments, fragment{field.Name, false, false})...)
 				})
 			}
 		}
@@ -269,7 +443,7 @@ func (o Options) analyzeStruct(spec reflect.Type, collect func(setterFunc, ...fr
 func (o Options) analyzeMap(spec reflect.Type, collect func(setterFunc, ...fragment)) {
 	keySpec := spec.Key()
 	valueSpec := spec.Elem()
-	if isPrimitive(keySpec) {
+	if o.isPrimitive(keySpec) {
 		_collect := func(set setterFunc, fragments ...fragment) {
 			setter := func(target reflect.Value, values ...string) error {
 				if target.Kind() != reflect.Map {
@@ -277,7 +451,7 @@ func (o Options) analyzeMap(spec reflect.Type, collect func(setterFunc, ...fragm
 				}
 
 				keyElem := reflect.New(keySpec).Elem()
-				if err := setPrimitive(keyElem, values[0]); err != nil {
+				if err := o.forKey().setPrimitive(keyElem, values[0]); err != nil {
 					return err
 				}
 
@@ -297,25 +471,71 @@ func (o Options) analyzeMap(spec reflect.Type, collect func(setterFunc, ...fragm
 			collect(setter, fragments...)
 		}
 
-		if isPrimitive(valueSpec) {
+		if o.isPrimitive(valueSpec) {
 			setter := func(target reflect.Value, values ...string) error {
-				return setPrimitive(target, values[0])
+				return o.setPrimitive(target, values[0])
 			}
-			_collect(setter, fragment{o.Map.KeyPattern, true})
+			_collect(setter, fragment{o.Map.KeyPattern, true, o.acceptsPresence(valueSpec)})
+		} else if isEmptyStruct(valueSpec) {
+			setter := func(target reflect.Value, values ...string) error {
+				return o.setSetMember(target, values[0], values[1])
+			}
+			collect(setter, fragment{o.Map.KeyPattern, true, o.Bool.Presence})
 		} else {
 			o.analyze(valueSpec, func(setter setterFunc, fragments ...fragment) {
-				_collect(setter, append(fragments, fragment{o.Map.KeyPattern, true})...)
+				_collect(setter, append(fragments, fragment{o.Map.KeyPattern, true, false})...)
 			})
 		}
 	}
 }
 
 func (o Options) analyzeSlice(spec reflect.Type, collect func(setterFunc, ...fragment)) {
+	o.analyzeIndexed(spec, collect, func(target reflect.Value, index int) (reflect.Value, error) {
+		if index >= o.Slice.MaxLength {
+			return reflect.Value{}, fmt.Errorf("invalid index: %d exceeds max length %d", index+o.Slice.FirstIndex, o.Slice.MaxLength)
+		}
+
+		length := index + 1
+		capacity := 16
+
+		for capacity < length {
+			capacity *= 2
+		}
+
+		if target.IsNil() {
+			target.Set(reflect.MakeSlice(target.Type(), length, capacity))
+		}
+
+		if capacity > target.Cap() {
+			slice := reflect.MakeSlice(target.Type(), target.Len(), capacity)
+			reflect.Copy(slice, target)
+			target.Set(slice)
+		}
+
+		if length > target.Len() {
+			target.SetLen(length)
+		}
+
+		return target.Index(index), nil
+	})
+}
+
+func (o Options) analyzeArray(spec reflect.Type, collect func(setterFunc, ...fragment)) {
+	o.analyzeIndexed(spec, collect, func(target reflect.Value, index int) (reflect.Value, error) {
+		if index >= target.Len() {
+			return reflect.Value{}, fmt.Errorf("invalid index: %d exceeds last index %d", index+o.Slice.FirstIndex, target.Len()-1+o.Slice.FirstIndex)
+		}
+
+		return target.Index(index), nil
+	})
+}
+
+func (o Options) analyzeIndexed(spec reflect.Type, collect func(setterFunc, ...fragment), element func(reflect.Value, int) (reflect.Value, error)) {
 	elementSpec := spec.Elem()
 	_collect := func(set setterFunc, fragments ...fragment) {
 		setter := func(target reflect.Value, values ...string) error {
-			if target.Kind() != reflect.Slice {
-				return fmt.Errorf("invalid type: expected %s but got %s", reflect.Slice, target.Kind())
+			if target.Kind() != spec.Kind() {
+				return fmt.Errorf("invalid type: expected %s but got %s", spec.Kind(), target.Kind())
 			}
 
 			index, err := strconv.Atoi(values[0])
@@ -323,42 +543,28 @@ func (o Options) analyzeSlice(spec reflect.Type, collect func(setterFunc, ...fra
 				return err
 			}
 
-			length := index - o.Slice.FirstIndex + 1
-			capacity := 16
-
-			for capacity < length {
-				capacity *= 2
-			}
-
-			if target.IsNil() {
-				target.Set(reflect.MakeSlice(target.Type(), length, capacity))
-			}
-
-			if capacity > target.Cap() {
-				slice := reflect.MakeSlice(target.Type(), target.Len(), capacity)
-				reflect.Copy(slice, target)
-				target.Set(slice)
+			if index < o.Slice.FirstIndex {
+				return fmt.Errorf("invalid index: %d is lower than first index %d", index, o.Slice.FirstIndex)
 			}
 
-			element := target.Index(index - o.Slice.FirstIndex)
-
-			if err := set(element, values[1:]...); err != nil {
+			elem, err := element(target, index-o.Slice.FirstIndex)
+			if err != nil {
 				return err
 			}
 
-			return nil
+			return set(elem, values[1:]...)
 		}
 		collect(setter, fragments...)
 	}
 
-	if isPrimitive(elementSpec) {
+	if o.isPrimitive(elementSpec) {
 		setter := func(target reflect.Value, values ...string) error {
-			return setPrimitive(target, values[0])
+			return o.setPrimitive(target, values[0])
 		}
-		_collect(setter, fragment{o.Slice.IndexPattern, true})
+		_collect(setter, fragment{o.Slice.IndexPattern, true, o.acceptsPresence(elementSpec)})
 	} else {
 		o.analyze(elementSpec, func(setter setterFunc, fragments ...fragment) {
-			_collect(setter, append(fragments, fragment{o.Slice.IndexPattern, true})...)
+			_collect(setter, append(fragments, fragment{o.Slice.IndexPattern, true, false})...)
 		})
 	}
 }
@@ -371,36 +577,131 @@ func indirect(spec reflect.Type) reflect.Type {
 	return spec
 }
 
-func isPrimitive(spec reflect.Type) bool {
-	spec = indirect(spec)
+func allocate(target reflect.Value) reflect.Value {
+	for target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	return target
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func isTextUnmarshaler(spec reflect.Type) bool {
+	return implements(spec, textUnmarshalerType)
+}
+
+// implements reports whether a pointer to spec implements iface. A struct
+// embedding an implementation next to other fields is walked instead, even
+// if it declares the method itself.
+func implements(spec reflect.Type, iface reflect.Type) bool {
+	if spec.Kind() == reflect.Ptr || !reflect.PointerTo(spec).Implements(iface) {
+		return false
+	}
+	if spec.Kind() != reflect.Struct || spec.NumField() < 2 {
+		return true
+	}
+
+	for i := 0; i < spec.NumField(); i++ {
+		field := spec.Field(i)
+		if field.Anonymous && (field.Type.Implements(iface) || reflect.PointerTo(field.Type).Implements(iface)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o Options) isPrimitive(spec reflect.Type) bool {
+	for spec.Kind() == reflect.Ptr {
+		if _, ok := o.decoder(spec); ok {
+			return true
+		}
+		spec = spec.Elem()
+	}
+
+	_, ok := o.decoder(spec)
 
-	return spec.Kind() == reflect.String ||
+	return ok || o.json || isTextUnmarshaler(spec) || isJSONUnmarshaler(spec) || isBytes(spec) ||
+		spec.Kind() == reflect.String ||
 		spec.Kind() >= reflect.Bool && spec.Kind() <= reflect.Complex128
 }
 
-func isPrimitiveMap(spec reflect.Type) bool {
+func (o Options) isPrimitiveMap(spec reflect.Type) bool {
 	spec = indirect(spec)
 
-	return spec.Kind() == reflect.Map && isPrimitive(spec.Key()) && isPrimitive(spec.Elem())
+	return spec.Kind() == reflect.Map && o.isPrimitive(spec.Key()) && (o.isInline(spec.Elem()) || isEmptyStruct(spec.Elem()))
 }
 
-func isPrimitiveSlice(spec reflect.Type) bool {
+func (o Options) isPrimitiveSlice(spec reflect.Type) bool {
 	spec = indirect(spec)
 
-	return spec.Kind() == reflect.Slice && isPrimitive(spec.Elem())
+	return (spec.Kind() == reflect.Slice || spec.Kind() == reflect.Array) && o.isInline(spec.Elem())
 }
 
-func setPrimitive(target reflect.Value, value string) error {
-	for target.Kind() == reflect.Ptr {
+func (o Options) setPrimitive(target reflect.Value, value string) error {
+	value, err := o.checkEnum(target.Type(), value)
+	if err != nil {
+		return err
+	}
+
+	if o.json {
+		return decodeJSON(target, value)
+	}
+
+	for {
+		if decode, ok := o.decoder(target.Type()); ok {
+			return setDecoded(target, decode, value)
+		}
+
+		if target.Kind() != reflect.Ptr {
+			break
+		}
 		if target.IsNil() {
 			target.Set(reflect.New(target.Type().Elem()))
 		}
 		target = target.Elem()
 	}
 
+	switch target.Type() {
+	case durationType:
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		target.SetInt(int64(duration))
+		return nil
+	case timeType:
+		timeValue, err := time.Parse(o.timeLayout(), value)
+		if err != nil {
+			return err
+		}
+		target.Set(reflect.ValueOf(timeValue))
+		return nil
+	}
+
+	if isTextUnmarshaler(target.Type()) {
+		return target.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	if isJSONUnmarshaler(target.Type()) {
+		return decodeJSON(target, value)
+	}
+
+	if isBytes(target.Type()) {
+		decoded, err := decodeBytes(o.ByteEncoding, value)
+		if err != nil {
+			return err
+		}
+		target.SetBytes(decoded)
+		return nil
+	}
+
 	switch target.Kind() {
 	case reflect.Bool:
-		boolValue, err := strconv.ParseBool(value)
+		boolValue, err := o.parseBool(value)
 		if err != nil {
 			return err
 		}
@@ -408,25 +709,25 @@ func setPrimitive(target reflect.Value, value string) error {
 	case reflect.String:
 		target.SetString(value)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		intValue, err := strconv.ParseInt(value, 10, 64)
+		intValue, err := o.parseInt(value, target.Type().Bits())
 		if err != nil {
 			return err
 		}
 		target.SetInt(intValue)
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		uintValue, err := strconv.ParseUint(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		uintValue, err := o.parseUint(value, target.Type().Bits())
 		if err != nil {
 			return err
 		}
 		target.SetUint(uintValue)
 	case reflect.Float32, reflect.Float64:
-		floatValue, err := strconv.ParseFloat(value, 64)
+		floatValue, err := strconv.ParseFloat(o.numberLiteral(value), target.Type().Bits())
 		if err != nil {
 			return err
 		}
 		target.SetFloat(floatValue)
 	case reflect.Complex64, reflect.Complex128:
-		complexValue, err := strconv.ParseComplex(value, 64)
+		complexValue, err := strconv.ParseComplex(o.numberLiteral(value), target.Type().Bits())
 		if err != nil {
 			return err
 		}
@@ -437,35 +738,52 @@ func setPrimitive(target reflect.Value, value string) error {
 	return nil
 }
 
-func (o Options) setPrimitiveMap(spec reflect.Type, target reflect.Value, token string) error {
-	pairs := strings.Split(token, o.Map.EntrySeparator)
+func (o Options) setPrimitiveMap(spec reflect.Type, target reflect.Value, token string, groups []string) error {
+	target, spec = allocate(target), indirect(spec)
+	entries, err := o.parseEntries(spec.Elem(), token, groups)
+	if err != nil {
+		return err
+	}
 	target.Set(reflect.MakeMap(spec))
 
-	for _, pair := range pairs {
-		tokens := strings.SplitN(pair, o.Map.KeyValueSeparator, 2)
-
+	for _, entry := range entries {
 		key := reflect.New(spec.Key()).Elem()
-		if err := setPrimitive(key, strings.TrimSpace(tokens[0])); err != nil {
+		if err := o.forKey().setPrimitive(key, entry[0]); err != nil {
 			return err
 		}
 
 		value := reflect.New(spec.Elem()).Elem()
-		if err := setPrimitive(value, strings.TrimSpace(tokens[1])); err != nil {
-			return err
+		if !isEmptyStruct(spec.Elem()) {
+			if err := o.setInline(spec.Elem(), value, entry[1], innerGroups(groups)); err != nil {
+				return err
+			}
 		}
 
-		target.SetMapIndex(key, value)
+		if err := o.setMapEntry(target, key, value); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (o Options) setPrimitiveSlice(spec reflect.Type, target reflect.Value, token string) error {
-	values := strings.Split(token, o.Slice.ElementSeparator)
-	target.Set(reflect.MakeSlice(spec, len(values), len(values)))
+func (o Options) setPrimitiveSlice(spec reflect.Type, target reflect.Value, token string, groups []string) error {
+	target, spec = allocate(target), indirect(spec)
+	values, err := o.parseElements(spec.Elem(), token, groups)
+	if err != nil {
+		return err
+	}
+	if spec.Kind() == reflect.Array {
+		if len(values) != spec.Len() {
+			return fmt.Errorf("invalid length: expected %d elements but got %d", spec.Len(), len(values))
+		}
+		target.Set(reflect.Zero(spec))
+	} else {
+		target.Set(reflect.MakeSlice(spec, len(values), len(values)))
+	}
 
 	for index, element := range values {
-		if err := setPrimitive(target.Index(index), strings.TrimSpace(element)); err != nil {
+		if err := o.setInline(spec.Elem(), target.Index(index), element, innerGroups(groups)); err != nil {
 			return err
 		}
 	}
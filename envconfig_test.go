@@ -437,6 +437,20 @@ func TestPrimitiveMapValue(t *testing.T) {
 	})
 }
 
+func TestEmptyTemplatedValue(t *testing.T) {
+	type TestSpec struct {
+		MapField   map[string]string
+		SliceField []string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"MAP_FIELD_K": "", "SLICE_FIELD_1": ""}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]string{"K": ""}, spec.MapField)
+	assert.Equal(t, []string{"", ""}, spec.SliceField)
+}
+
 func TestStructAsMapValue(t *testing.T) {
 	type ChildSpec struct {
 		TestField string
@@ -501,6 +515,48 @@ func TestPrimitiveSliceElement(t *testing.T) {
 	})
 }
 
+func TestMultipleSliceElements(t *testing.T) {
+	type TestSpec struct {
+		SliceField []string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"SLICE_FIELD_0": "a", "SLICE_FIELD_1": "b", "SLICE_FIELD_20": "c"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Len(t, spec.SliceField, 21)
+	assert.Equal(t, "a", spec.SliceField[0])
+	assert.Equal(t, "b", spec.SliceField[1])
+	assert.Equal(t, "c", spec.SliceField[20])
+}
+
+func TestSliceIndexLimit(t *testing.T) {
+	type TestSpec struct {
+		SliceField []string
+	}
+
+	testCases := map[string]string{
+		"SLICE_FIELD_1023":                "",
+		"SLICE_FIELD_1024":                "SLICE_FIELD_1024: invalid index: 1024 exceeds max length 1024",
+		"SLICE_FIELD_99999999999999":      "SLICE_FIELD_99999999999999: invalid index: 99999999999999 exceeds max length 1024",
+		"SLICE_FIELD_9223372036854775807": "SLICE_FIELD_9223372036854775807: invalid index: 9223372036854775807 exceeds max length 1024",
+	}
+
+	for key, expected := range testCases {
+		t.Run(key, func(t *testing.T) {
+			options := DefaultOptions()
+			options.Source = MapSource{key: "x"}
+
+			err := InitWithOptions(&TestSpec{}, options)
+			if expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, expected)
+			}
+		})
+	}
+}
+
 func TestStructAsSliceElement(t *testing.T) {
 	type ChildSpec struct {
 		TestField string
@@ -0,0 +1,177 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type expander struct {
+	options Options
+	source  Source
+	stack   []string
+}
+
+func (o Options) expand(source Source, name, value string) (string, error) {
+	e := expander{options: o, source: source, stack: []string{name}}
+	expanded, err := e.expand(value)
+	if err != nil {
+		return "", &VariableError{Name: name, Err: err}
+	}
+	return expanded, nil
+}
+
+func (e *expander) expand(value string) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var result strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			result.WriteByte(value[i])
+			continue
+		}
+
+		switch next := value[i+1]; {
+		case next == '$':
+			result.WriteByte('$')
+			i++
+		case next == '{':
+			end := matchingBrace(value, i+2)
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable reference in %q", value)
+			}
+			expanded, err := e.expandBraced(value[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(expanded)
+			i = end
+		case isNameStart(next):
+			end := i + 2
+			for end < len(value) && isNameChar(value[end]) {
+				end++
+			}
+			expanded, _, err := e.resolve(value[i+1 : end])
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(expanded)
+			i = end - 1
+		default:
+			result.WriteByte('$')
+		}
+	}
+	return result.String(), nil
+}
+
+func (e *expander) expandBraced(expression string) (string, error) {
+	end := 0
+	for end < len(expression) && isNameChar(expression[end]) {
+		end++
+	}
+
+	name, operator := expression[:end], expression[end:]
+	if name == "" || !isNameStart(name[0]) {
+		return "", fmt.Errorf("invalid variable reference ${%s}", expression)
+	}
+
+	value, ok, err := e.resolve(name)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case operator == "":
+		return value, nil
+	case strings.HasPrefix(operator, ":-"):
+		if value == "" {
+			return e.expand(operator[2:])
+		}
+		return value, nil
+	case strings.HasPrefix(operator, "-"):
+		if !ok {
+			return e.expand(operator[1:])
+		}
+		return value, nil
+	case strings.HasPrefix(operator, ":?"):
+		if value == "" {
+			return "", e.required(name, operator[2:])
+		}
+		return value, nil
+	case strings.HasPrefix(operator, "?"):
+		if !ok {
+			return "", e.required(name, operator[1:])
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("invalid variable reference ${%s}", expression)
+	}
+}
+
+func (e *expander) resolve(name string) (string, bool, error) {
+	for i, parent := range e.stack {
+		if parent == name {
+			cycle := append(append([]string{}, e.stack[i:]...), name)
+			return "", false, fmt.Errorf("variable reference cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	fileName := ""
+	if e.options.File.Enabled {
+		fileName = name + e.options.File.Suffix
+	}
+
+	value, _, err := e.options.lookup(e.source, name, fileName)
+	if err != nil {
+		return "", false, err
+	}
+
+	ok := e.options.present(e.source, name, fileName)
+
+	e.stack = append(e.stack, name)
+	defer func() {
+		e.stack = e.stack[:len(e.stack)-1]
+	}()
+
+	value, err = e.expand(value)
+	return value, ok, err
+}
+
+func (e *expander) required(name, message string) error {
+	if message == "" {
+		message = "parameter null or not set"
+	} else {
+		expanded, err := e.expand(message)
+		if err != nil {
+			return err
+		}
+		message = expanded
+	}
+	return errors.New(name + ": " + message)
+}
+
+func matchingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9'
+}
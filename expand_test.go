@@ -0,0 +1,132 @@
+package envconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	source := MapSource{
+		"DB_USER":  "admin",
+		"DB_HOST":  "db.local",
+		"DB_EMPTY": "",
+		"DB_URL":   "postgres://${DB_USER}@$DB_HOST/app",
+	}
+
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"postgres://${DB_USER}@$DB_HOST/app", "postgres://admin@db.local/app"},
+		{"${DB_URL}?sslmode=off", "postgres://admin@db.local/app?sslmode=off"},
+		{"${DB_PORT:-5432}", "5432"},
+		{"${DB_EMPTY:-fallback}", "fallback"},
+		{"${DB_EMPTY-fallback}", ""},
+		{"${DB_PORT-${DB_HOST}}", "db.local"},
+		{"cost: $$5", "cost: $5"},
+		{"$ alone", "$ alone"},
+		{"${UNSET}", ""},
+	}
+
+	options := DefaultOptions()
+	options.Source = source
+	options.Expand = true
+
+	for _, testCase := range testCases {
+		value, err := options.expand(source, "VALUE", testCase.value)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, value)
+	}
+}
+
+func TestExpandField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		URL      string
+		MapField map[string]int
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"HOST":          "db.local",
+		"PORT":          "5432",
+		"URL":           "http://${HOST}:${PORT}",
+		"MAP_FIELD_KEY": "$PORT",
+	}
+	options.Expand = true
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "http://db.local:5432", spec.URL)
+	assert.Equal(t, map[string]int{"KEY": 5432}, spec.MapField)
+}
+
+func TestExpandDisabled(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		URL string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"HOST": "db.local", "URL": "http://${HOST}"}
+	options.Expand = false
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "http://${HOST}", spec.URL)
+}
+
+func TestExpandRequired(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		URL string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"URL": "http://${HOST:?host is required}"}
+	options.Expand = true
+
+	spec := TestSpec{}
+	err := InitWithOptions(&spec, options)
+	var variableErr *VariableError
+	assert.True(t, errors.As(err, &variableErr))
+	assert.Equal(t, "URL", variableErr.Name)
+	assert.EqualError(t, err, "URL: HOST: host is required")
+}
+
+func TestExpandCycle(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		First string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"FIRST": "${SECOND}", "SECOND": "x${THIRD}", "THIRD": "$FIRST"}
+	options.Expand = true
+
+	spec := TestSpec{}
+	assert.EqualError(t, InitWithOptions(&spec, options), "FIRST: variable reference cycle: FIRST -> SECOND -> THIRD -> FIRST")
+}
+
+func TestExpandIgnoresUnrelated(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Labels map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"UNRELATED": "${NOPE:?unset}",
+		"PS1":       "${debian_chroot:+($debian_chroot)}\\u@\\h:\\w\\$ ",
+		"LABELS_A":  "x",
+	}
+	options.Expand = true
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]string{"A": "x"}, spec.Labels)
+}
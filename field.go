@@ -0,0 +1,87 @@
+package envconfig
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+var timeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+func (o Options) withField(field reflect.StructField) Options {
+	o.enum = nil
+	if enum, ok := field.Tag.Lookup("enum"); ok {
+		o.enum = splitTag(enum)
+	}
+	if layout, ok := field.Tag.Lookup("layout"); ok {
+		o.TimeLayout = layout
+	}
+	if quantity, err := strconv.ParseBool(field.Tag.Get("quantity")); err == nil {
+		o.Quantities = quantity
+	}
+	o.json = false
+	if encoding, ok := field.Tag.Lookup("encoding"); ok {
+		if encoding == EncodingJSON {
+			o.json = true
+		} else {
+			o.ByteEncoding = encoding
+		}
+	}
+	if groups, ok := field.Tag.Lookup("groups"); ok {
+		o.GroupSeparators = strings.Fields(groups)
+	}
+	if mode, ok := field.Tag.Lookup("bool"); ok {
+		o = o.withBoolTag(mode)
+	}
+	if truthy, ok := field.Tag.Lookup("truthy"); ok {
+		o.Bool.True = splitTag(truthy)
+	}
+	if falsy, ok := field.Tag.Lookup("falsy"); ok {
+		o.Bool.False = splitTag(falsy)
+	}
+	return o
+}
+
+func (o Options) timeLayout() string {
+	if layout, ok := timeLayouts[o.TimeLayout]; ok {
+		return layout
+	}
+	if o.TimeLayout == "" {
+		return time.RFC3339
+	}
+	return o.TimeLayout
+}
+
+func (o Options) forKey() Options {
+	o.enum = nil
+	o.json = false
+	return o
+}
+
+func splitTag(tag string) []string {
+	values := strings.Split(tag, ",")
+	for i, value := range values {
+		values[i] = strings.TrimSpace(value)
+	}
+	return values
+}
@@ -0,0 +1,93 @@
+package envconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestDurationField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Timeout   time.Duration
+		Pointer   *time.Duration
+		Inline    []time.Duration
+		Indexed   []time.Duration
+		Templated map[string]time.Duration
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"TIMEOUT":          "30s",
+		"POINTER":          "1m30s",
+		"INLINE":           "1s, 2ms",
+		"INDEXED_0":        "1h",
+		"TEMPLATED_WORKER": "250ms",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, 30*time.Second, spec.Timeout)
+	assert.Equal(t, 90*time.Second, *spec.Pointer)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Millisecond}, spec.Inline)
+	assert.Equal(t, []time.Duration{time.Hour}, spec.Indexed)
+	assert.Equal(t, map[string]time.Duration{"WORKER": 250 * time.Millisecond}, spec.Templated)
+}
+
+func TestDurationFieldInvalid(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Timeout time.Duration
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TIMEOUT": "30000000000"}
+
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+}
+
+func TestTimeField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Start    time.Time
+		Date     time.Time            `layout:"2006-01-02"`
+		Named    *time.Time           `layout:"DateTime"`
+		Holidays []time.Time          `layout:"DateOnly"`
+		Events   map[string]time.Time `layout:"DateOnly"`
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"START":         "2024-05-01T10:00:00Z",
+		"DATE":          "2024-12-24",
+		"NAMED":         "2024-01-02 03:04:05",
+		"HOLIDAYS":      "2024-12-25,2024-12-26",
+		"EVENTS_LAUNCH": "2025-01-01",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), spec.Start)
+	assert.Equal(t, time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC), spec.Date)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), *spec.Named)
+	assert.Equal(t, []time.Time{time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)}, spec.Holidays)
+	assert.Equal(t, map[string]time.Time{"LAUNCH": time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}, spec.Events)
+}
+
+func TestTimeLayoutOption(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Start time.Time
+		Other time.Time `layout:"RFC3339"`
+	}
+	options := DefaultOptions()
+	options.TimeLayout = time.DateOnly
+	options.Source = MapSource{"START": "2024-05-01", "OTHER": "2024-05-01T10:00:00Z"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), spec.Start)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), spec.Other)
+}
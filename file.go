@@ -0,0 +1,96 @@
+package envconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+func (o Options) lookup(source Source, name, fileName string) (string, string, error) {
+	value, _ := source.Lookup(name)
+	if fileName == "" {
+		return value, name, nil
+	}
+
+	path, _ := source.Lookup(fileName)
+	if path == "" {
+		return value, name, nil
+	}
+
+	if value != "" {
+		conflict := o.File.Conflict
+		if ranked, ok := source.(rankedSource); ok {
+			switch variableRank, fileRank := ranked.rank(name), ranked.rank(fileName); {
+			case variableRank > fileRank:
+				conflict = FilePreferVariable
+			case fileRank > variableRank:
+				conflict = FilePreferFile
+			}
+		}
+
+		switch conflict {
+		case FilePreferVariable:
+			return value, name, nil
+		case FilePreferFile:
+		default:
+			return "", "", &VariableError{Name: name, Err: fmt.Errorf("both %s and %s are set", name, fileName)}
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", &VariableError{Name: fileName, Err: err}
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), fileName, nil
+}
+
+func (o Options) present(source Source, name, fileName string) bool {
+	if _, ok := source.Lookup(name); ok {
+		return true
+	}
+	if fileName == "" {
+		return false
+	}
+	_, ok := source.Lookup(fileName)
+	return ok
+}
+
+func (o Options) templateCandidates(keys []string) ([]string, map[string]string) {
+	files := make(map[string]string)
+	if !o.File.Enabled || o.File.Suffix == "" {
+		return keys, files
+	}
+
+	names := make([]string, 0, len(keys))
+	direct := make(map[string]bool)
+	for _, key := range keys {
+		if name, ok := o.trimFileSuffix(key); ok {
+			files[name] = key
+		} else {
+			direct[key] = true
+			names = append(names, key)
+		}
+	}
+
+	for _, key := range keys {
+		if name, ok := o.trimFileSuffix(key); ok && !direct[name] {
+			names = append(names, name)
+		}
+	}
+
+	return names, files
+}
+
+func (o Options) trimFileSuffix(key string) (string, bool) {
+	suffix := o.File.Suffix
+	if len(key) <= len(suffix) {
+		return "", false
+	}
+
+	name, tail := key[:len(key)-len(suffix)], key[len(key)-len(suffix):]
+	if tail == suffix || !o.MatchCase && strings.EqualFold(tail, suffix) {
+		return name, true
+	}
+	return "", false
+}
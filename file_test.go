@@ -0,0 +1,218 @@
+package envconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func writeSecret(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileSuffixField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TEST_FIELD_FILE": writeSecret(t, "secret\n")}
+	options.File.Enabled = true
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "secret", spec.TestField)
+}
+
+func TestFileSuffixTemplate(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		MapField map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"MAP_FIELD_FIRST_FILE":  writeSecret(t, "first\n"),
+		"map_field_second_file": writeSecret(t, "second"),
+		"MAP_FIELD_THIRD":       "third",
+	}
+	options.File.Enabled = true
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]string{
+		"FIRST":  "first",
+		"second": "second",
+		"THIRD":  "third",
+	}, spec.MapField)
+}
+
+func TestFileSuffixDisabled(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TEST_FIELD_FILE": writeSecret(t, "secret")}
+	options.File.Enabled = false
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "", spec.TestField)
+}
+
+func TestFileSuffixCustom(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TEST_FIELD__PATH": writeSecret(t, "secret")}
+	options.File.Enabled = true
+	options.File.Suffix = "__PATH"
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "secret", spec.TestField)
+}
+
+func TestFileSuffixConflict(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+
+	testCases := []struct {
+		name     string
+		conflict FileConflict
+		expected string
+	}{
+		{
+			name:     "PreferVariable",
+			conflict: FilePreferVariable,
+			expected: "variable",
+		},
+		{
+			name:     "PreferFile",
+			conflict: FilePreferFile,
+			expected: "file",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.Source = MapSource{
+				"TEST_FIELD":      "variable",
+				"TEST_FIELD_FILE": writeSecret(t, "file"),
+			}
+			options.File.Enabled = true
+			options.File.Conflict = testCase.conflict
+
+			spec := TestSpec{}
+			assert.NoError(t, InitWithOptions(&spec, options))
+			assert.Equal(t, testCase.expected, spec.TestField)
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		options := DefaultOptions()
+		options.Source = MapSource{
+			"TEST_FIELD":      "variable",
+			"TEST_FIELD_FILE": writeSecret(t, "file"),
+		}
+		options.File.Enabled = true
+
+		spec := TestSpec{}
+		err := InitWithOptions(&spec, options)
+		var variableErr *VariableError
+		assert.True(t, errors.As(err, &variableErr))
+		assert.Equal(t, "TEST_FIELD", variableErr.Name)
+	})
+}
+
+func TestFileSuffixMissingFile(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TEST_FIELD_FILE": filepath.Join(t.TempDir(), "missing")}
+	options.File.Enabled = true
+
+	spec := TestSpec{}
+	err := InitWithOptions(&spec, options)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestFileSuffixIgnoresUnrelated(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Labels map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"UNRELATED_FILE": filepath.Join(t.TempDir(), "missing"), "LABELS_A": "x"}
+	options.File.Enabled = true
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]string{"A": "x"}, spec.Labels)
+}
+
+func TestFileSuffixLayers(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		DbPassword string
+	}
+	secret := writeSecret(t, "from-file")
+
+	testCases := map[string]struct {
+		source   LayeredSource
+		expected string
+	}{
+		"FileInHigherLayer": {
+			Layers(
+				Layer{Name: "defaults", Source: MapSource{"DB_PASSWORD": "dev"}},
+				Layer{Name: "env", Source: MapSource{"DB_PASSWORD_FILE": secret}},
+			),
+			"from-file",
+		},
+		"VariableInHigherLayer": {
+			Layers(
+				Layer{Name: "defaults", Source: MapSource{"DB_PASSWORD_FILE": secret}},
+				Layer{Name: "env", Source: MapSource{"DB_PASSWORD": "prod"}},
+			),
+			"prod",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.Source = testCase.source
+			options.File.Enabled = true
+			options.File.Conflict = FilePreferVariable
+
+			spec := TestSpec{}
+			assert.NoError(t, InitWithOptions(&spec, options))
+			assert.Equal(t, testCase.expected, spec.DbPassword)
+		})
+	}
+
+	options := DefaultOptions()
+	options.Source = Layers(Layer{Name: "env", Source: MapSource{"DB_PASSWORD": "dev", "DB_PASSWORD_FILE": secret}})
+	options.File.Enabled = true
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), "DB_PASSWORD: both DB_PASSWORD and DB_PASSWORD_FILE are set")
+}
@@ -0,0 +1,236 @@
+package envconfig
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func BindFlags(flags *flag.FlagSet, spec any, options Options) error {
+	target := reflect.ValueOf(spec)
+
+	if target.Kind() != reflect.Pointer {
+		return ErrInvalidSpecification
+	}
+
+	values := make(map[string]flag.Value)
+	usages := make(map[string]string)
+	names := make([]string, 0)
+
+	options.analyze(target.Type(), func(setter setterFunc, fragments ...fragment) {
+		path := make([]string, 0, len(fragments))
+		for i := len(fragments) - 1; i >= 1; i-- {
+			if fragments[i].dynamic {
+				return
+			}
+			path = append(path, fragments[i].pattern)
+		}
+
+		var value flag.Value
+		if fragments[0].dynamic {
+			field, ok := lookupField(target.Type(), path)
+			if !ok {
+				return
+			}
+			value = &repeatedFlag{
+				target:  target,
+				path:    path,
+				set:     setter,
+				spec:    indirect(field.Type),
+				options: options,
+			}
+		} else {
+			path = append(path, fragments[0].pattern)
+			field, ok := lookupField(target.Type(), path)
+			if !ok {
+				return
+			}
+			value = &leafFlag{
+				target:  target,
+				set:     setter,
+				boolean: indirect(field.Type).Kind() == reflect.Bool,
+			}
+		}
+
+		name := flagName(path)
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		} else if _, ok := value.(*leafFlag); ok {
+			return
+		}
+		values[name] = value
+
+		if field, ok := lookupField(target.Type(), path); ok {
+			usages[name] = options.withField(field).usage(field)
+		}
+	})
+
+	for _, name := range names {
+		if flags.Lookup(name) != nil {
+			return fmt.Errorf("flag redefined: %s", name)
+		}
+		flags.Var(values[name], name, usages[name])
+	}
+
+	return nil
+}
+
+func (o Options) usage(field reflect.StructField) string {
+	usage := field.Tag.Get("usage")
+
+	spec := indirect(field.Type)
+	if !o.isPrimitive(spec) && (spec.Kind() == reflect.Slice || spec.Kind() == reflect.Array || spec.Kind() == reflect.Map) {
+		spec = spec.Elem()
+	}
+
+	if allowed := o.allowedValues(spec); allowed != nil {
+		if usage != "" {
+			usage += " "
+		}
+		usage += "(one of: " + strings.Join(allowed, ", ") + ")"
+	}
+	return usage
+}
+
+type leafFlag struct {
+	target  reflect.Value
+	set     setterFunc
+	boolean bool
+	value   string
+}
+
+func (f *leafFlag) String() string {
+	return f.value
+}
+
+func (f *leafFlag) Set(value string) error {
+	if err := f.set(f.target, value); err != nil {
+		return err
+	}
+	f.value = value
+	return nil
+}
+
+func (f *leafFlag) IsBoolFlag() bool {
+	return f.boolean
+}
+
+type repeatedFlag struct {
+	target  reflect.Value
+	path    []string
+	set     setterFunc
+	spec    reflect.Type
+	options Options
+	values  []string
+}
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(f.values, " ")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	if len(f.values) == 0 {
+		field, err := resolveField(f.target, f.path)
+		if err != nil {
+			return err
+		}
+		field.Set(reflect.Zero(field.Type()))
+	}
+
+	var tokens []string
+	if f.spec.Kind() == reflect.Map {
+		key, element, err := f.options.splitEntry(value, false)
+		if member, ok, memberErr := f.options.setMember(f.spec.Elem(), value); ok || memberErr != nil {
+			key, element, err = member, "true", memberErr
+		}
+		if err != nil {
+			return err
+		}
+		tokens = []string{key, element}
+	} else {
+		tokens = []string{strconv.Itoa(f.options.Slice.FirstIndex + len(f.values)), value}
+	}
+
+	if err := f.set(f.target, tokens...); err != nil {
+		return err
+	}
+	f.values = append(f.values, value)
+	return nil
+}
+
+func lookupField(spec reflect.Type, path []string) (reflect.StructField, bool) {
+	var field reflect.StructField
+	for _, name := range path {
+		spec = indirect(spec)
+		if spec.Kind() != reflect.Struct {
+			return field, false
+		}
+
+		var ok bool
+		field, ok = spec.FieldByName(name)
+		if !ok {
+			return field, false
+		}
+		spec = field.Type
+	}
+	return field, len(path) > 0
+}
+
+func resolveField(target reflect.Value, path []string) (reflect.Value, error) {
+	for _, name := range path {
+		target = allocate(target)
+
+		field, ok := target.Type().FieldByName(name)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown field: %s", name)
+		}
+
+		for i := range field.Index {
+			if i > 0 {
+				target = allocate(target)
+			}
+			target = target.Field(field.Index[i])
+		}
+	}
+
+	return allocate(target), nil
+}
+
+func flagName(path []string) string {
+	words := make([]string, 0)
+	for _, name := range path {
+		for _, word := range splitWords(name) {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+	return strings.Join(words, "-")
+}
+
+func splitWords(name string) []string {
+	words := make([]string, 0)
+	runes := []rune(name)
+	start := 0
+
+	for i := 1; i <= len(runes); i++ {
+		boundary := i == len(runes)
+		if !boundary {
+			previous, current := runes[i-1], runes[i]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			boundary = current == '_' || current == '-' || previous == '_' || previous == '-' ||
+				unicode.IsUpper(current) && (unicode.IsLower(previous) || unicode.IsDigit(previous) || unicode.IsUpper(previous) && nextIsLower)
+		}
+
+		if boundary {
+			word := strings.Trim(string(runes[start:i]), "_-")
+			if word != "" {
+				words = append(words, word)
+			}
+			start = i
+		}
+	}
+
+	return words
+}
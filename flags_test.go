@@ -0,0 +1,95 @@
+package envconfig
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestBindFlags(t *testing.T) {
+	type ChildSpec struct {
+		Host string `usage:"database host"`
+		Port int
+	}
+	type TestSpec struct {
+		Name    string `usage:"service name"`
+		Verbose bool
+		DB      ChildSpec
+		Tags    []string          `usage:"tags to apply"`
+		Labels  map[string]string `usage:"labels to apply"`
+		Workers []ChildSpec
+	}
+
+	spec := TestSpec{Tags: []string{"from-env"}}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	assert.NoError(t, BindFlags(flags, &spec, DefaultOptions()))
+
+	assert.Equal(t, "service name", flags.Lookup("name").Usage)
+	assert.Equal(t, "database host", flags.Lookup("db-host").Usage)
+	assert.Equal(t, "tags to apply", flags.Lookup("tags").Usage)
+	assert.Nil(t, flags.Lookup("workers"))
+
+	assert.NoError(t, flags.Parse([]string{
+		"-name", "app",
+		"-verbose",
+		"-db-host", "db.local",
+		"-db-port=5432",
+		"-tags", "a,b",
+		"-tags", "c",
+		"-labels", "team:core",
+		"-labels", "tier:backend",
+	}))
+
+	assert.Equal(t, "app", spec.Name)
+	assert.Equal(t, true, spec.Verbose)
+	assert.Equal(t, ChildSpec{Host: "db.local", Port: 5432}, spec.DB)
+	assert.Equal(t, []string{"a,b", "c"}, spec.Tags)
+	assert.Equal(t, map[string]string{"team": "core", "tier": "backend"}, spec.Labels)
+}
+
+func TestBindFlagsOverrideEnvironment(t *testing.T) {
+	type TestSpec struct {
+		TestField string
+		Other     string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TEST_FIELD": "env", "OTHER": "env"}
+
+	spec := TestSpec{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	assert.NoError(t, BindFlags(flags, &spec, options))
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.NoError(t, flags.Parse([]string{"--test-field", "flag"}))
+
+	assert.Equal(t, "flag", spec.TestField)
+	assert.Equal(t, "env", spec.Other)
+}
+
+func TestBindFlagsInvalidValue(t *testing.T) {
+	type TestSpec struct {
+		Port int
+	}
+
+	spec := TestSpec{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	assert.NoError(t, BindFlags(flags, &spec, DefaultOptions()))
+	assert.Error(t, flags.Parse([]string{"-port", "abc"}))
+}
+
+func TestFlagName(t *testing.T) {
+	testCases := map[string][]string{
+		"name":             {"Name"},
+		"db-host":          {"DB", "Host"},
+		"http-server-port": {"HTTPServer", "Port"},
+		"max-conns":        {"MaxConns"},
+		"ip-addr":          {"IPAddr"},
+		"snake-case":       {"snake_case"},
+	}
+
+	for expected, path := range testCases {
+		assert.Equal(t, expected, flagName(path))
+	}
+}
@@ -2,4 +2,4 @@ module grantslkuni/envconfig
 
 go 1.24.5
 
-require github.com/c2fo/testify v0.0.0-20150827203832-fba96363964a // indirect
+require github.com/c2fo/testify v0.0.0-20150827203832-fba96363964a
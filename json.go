@@ -0,0 +1,116 @@
+package envconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const EncodingJSON = "json"
+
+type JSONSyntax int
+
+const (
+	JSONSyntaxDisabled JSONSyntax = iota
+	JSONSyntaxAuto
+	JSONSyntaxRequired
+)
+
+type JSONError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("invalid JSON at offset %d: %s", e.Offset, e.Err)
+}
+
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+func isJSONUnmarshaler(spec reflect.Type) bool {
+	return implements(spec, jsonUnmarshalerType)
+}
+
+func decodeJSON(target reflect.Value, value string) error {
+	return unmarshalJSON(value, target.Addr().Interface())
+}
+
+func unmarshalJSON(value string, v any) error {
+	err := json.Unmarshal([]byte(value), v)
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return &JSONError{Offset: syntaxErr.Offset, Err: err}
+	case errors.As(err, &typeErr):
+		return &JSONError{Offset: typeErr.Offset, Err: err}
+	}
+	return err
+}
+
+func (s JSONSyntax) matches(value string, open, close byte) bool {
+	switch s {
+	case JSONSyntaxRequired:
+		return true
+	case JSONSyntaxAuto:
+		value = strings.TrimSpace(value)
+		return len(value) >= 2 && value[0] == open && value[len(value)-1] == close
+	default:
+		return false
+	}
+}
+
+func jsonElements(spec reflect.Type, value string) ([]string, error) {
+	var elements []json.RawMessage
+	if err := unmarshalJSON(value, &elements); err != nil {
+		return nil, err
+	}
+
+	values := make([]string, len(elements))
+	for i, element := range elements {
+		values[i] = jsonScalar(spec, element)
+	}
+	return values, nil
+}
+
+func jsonEntries(spec reflect.Type, value string) ([][2]string, error) {
+	var object map[string]json.RawMessage
+	if err := unmarshalJSON(value, &object); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([][2]string, len(keys))
+	for i, key := range keys {
+		entries[i] = [2]string{key, jsonScalar(spec, object[key])}
+	}
+	return entries, nil
+}
+
+func jsonScalar(spec reflect.Type, element json.RawMessage) string {
+	if spec = indirect(spec); isJSONUnmarshaler(spec) && !isTextUnmarshaler(spec) {
+		return string(element)
+	}
+
+	var text string
+	if err := json.Unmarshal(element, &text); err == nil {
+		return text
+	}
+	if string(element) == "null" {
+		return ""
+	}
+	return string(element)
+}
@@ -0,0 +1,155 @@
+package envconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/assert"
+)
+
+type route struct {
+	Path   string `json:"path"`
+	Weight int    `json:"weight"`
+}
+
+type upper string
+
+func (u *upper) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*u = upper(strings.ToUpper(value))
+	return nil
+}
+
+func TestJSONFieldTag(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Routes  []route        `encoding:"json"`
+		Default *route         `encoding:"json"`
+		Limits  map[string]int `encoding:"json"`
+		Labels  map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"ROUTES":        `[{"path":"/a","weight":2},{"path":"/b"}]`,
+		"DEFAULT":       `{"path":"/"}`,
+		"DEFAULT_PATH":  "/ignored",
+		"LIMITS":        `{"cpu":2}`,
+		"LIMITS_MEMORY": "4",
+		"LABELS":        "team:core",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []route{{"/a", 2}, {"/b", 0}}, spec.Routes)
+	assert.Equal(t, &route{Path: "/"}, spec.Default)
+	assert.Equal(t, map[string]int{"cpu": 2}, spec.Limits)
+	assert.Equal(t, map[string]string{"team": "core"}, spec.Labels)
+}
+
+func TestJSONUnmarshaler(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Name    upper
+		Pointer *upper
+		Names   []upper
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"NAME": `"core"`, "POINTER": `"edge"`, "NAMES_0": `"a"`}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, upper("CORE"), spec.Name)
+	assert.Equal(t, upper("EDGE"), *spec.Pointer)
+	assert.Equal(t, []upper{"A"}, spec.Names)
+}
+
+func TestJSONError(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Routes []route `encoding:"json"`
+	}
+	options := DefaultOptions()
+
+	options.Source = MapSource{"ROUTES": `[{"path":"/a",}]`}
+	err := InitWithOptions(&TestSpec{}, options)
+	var jsonErr *JSONError
+	assert.True(t, errors.As(err, &jsonErr))
+	assert.Equal(t, int64(15), jsonErr.Offset)
+	assert.EqualError(t, err, "ROUTES: invalid JSON at offset 15: invalid character '}' looking for beginning of object key string")
+
+	options.Source = MapSource{"ROUTES": `[{"weight":"heavy"}]`}
+	err = InitWithOptions(&TestSpec{}, options)
+	assert.True(t, errors.As(err, &jsonErr))
+	assert.Equal(t, int64(18), jsonErr.Offset)
+}
+
+func TestJSONCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts    []string
+		Ports    [2]uint16
+		Timeouts []time.Duration
+		Labels   map[string]string
+		Weights  map[color]float64
+		Names    []upper
+		Plain    []string
+	}
+	options := DefaultOptions()
+	RegisterDecoder(&options, parseColor)
+	options.Slice.JSON = JSONSyntaxAuto
+	options.Map.JSON = JSONSyntaxAuto
+	options.Source = MapSource{
+		"HOSTS":    ` ["a,1", "b"] `,
+		"PORTS":    "[80, 443]",
+		"TIMEOUTS": `["1s","2m"]`,
+		"LABELS":   `{"team":"core","url":"http://x:1"}`,
+		"WEIGHTS":  `{"red":0.5,"green":1}`,
+		"NAMES":    `["a","b"]`,
+		"PLAIN":    "a,b",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []string{"a,1", "b"}, spec.Hosts)
+	assert.Equal(t, [2]uint16{80, 443}, spec.Ports)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Minute}, spec.Timeouts)
+	assert.Equal(t, map[string]string{"team": "core", "url": "http://x:1"}, spec.Labels)
+	assert.Equal(t, map[color]float64{red: 0.5, green: 1}, spec.Weights)
+	assert.Equal(t, []upper{"A", "B"}, spec.Names)
+	assert.Equal(t, []string{"a", "b"}, spec.Plain)
+}
+
+func TestJSONCollectionSyntax(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts []string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"HOSTS": `["a","b"]`}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []string{`["a"`, `"b"]`}, spec.Hosts)
+
+	options.Slice.JSON = JSONSyntaxRequired
+	options.Source = MapSource{"HOSTS": "a,b"}
+	err := InitWithOptions(&TestSpec{}, options)
+	var jsonErr *JSONError
+	assert.True(t, errors.As(err, &jsonErr))
+	assert.Equal(t, int64(1), jsonErr.Offset)
+
+	options.Slice.JSON = JSONSyntaxAuto
+	options.Source = MapSource{"HOSTS": `["a",]`}
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), "HOSTS: invalid JSON at offset 6: invalid character ']' looking for beginning of value")
+}
@@ -0,0 +1,43 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func (o Options) isInline(spec reflect.Type) bool {
+	return o.isPrimitive(spec) || o.isPrimitiveMap(spec) || o.isPrimitiveSlice(spec)
+}
+
+func (o Options) nesting(spec reflect.Type) int {
+	if o.isPrimitive(spec) || isEmptyStruct(spec) {
+		return 0
+	}
+	return 1 + o.nesting(indirect(spec).Elem())
+}
+
+func (o Options) setCollection(spec reflect.Type, target reflect.Value, token string) error {
+	levels := o.nesting(spec) - 1
+	if len(o.GroupSeparators) < levels {
+		return fmt.Errorf("missing group separator for nesting level %d of %d", len(o.GroupSeparators)+1, levels+1)
+	}
+	return o.setInline(spec, target, token, o.GroupSeparators[:levels])
+}
+
+func (o Options) setInline(spec reflect.Type, target reflect.Value, token string, groups []string) error {
+	switch {
+	case o.isPrimitive(spec):
+		return o.setPrimitive(target, token)
+	case indirect(spec).Kind() == reflect.Map:
+		return o.setPrimitiveMap(spec, target, token, groups)
+	default:
+		return o.setPrimitiveSlice(spec, target, token, groups)
+	}
+}
+
+func innerGroups(groups []string) []string {
+	if len(groups) == 0 {
+		return nil
+	}
+	return groups[1:]
+}
@@ -0,0 +1,78 @@
+package envconfig
+
+import (
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestNestedCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Groups  [][]int
+		Routes  map[string][]string
+		Limits  map[string]map[string]int
+		Matrix  [][2]int
+		Triples [][][]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"GROUPS":  "1,2; 3",
+		"ROUTES":  "api:/a,/b;web:/",
+		"LIMITS":  "cpu:min:1,max:4;memory:max:8",
+		"MATRIX":  "1,2;3,4",
+		"TRIPLES": "a,b|c;d",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, [][]int{{1, 2}, {3}}, spec.Groups)
+	assert.Equal(t, map[string][]string{"api": {"/a", "/b"}, "web": {"/"}}, spec.Routes)
+	assert.Equal(t, map[string]map[string]int{"cpu": {"min": 1, "max": 4}, "memory": {"max": 8}}, spec.Limits)
+	assert.Equal(t, [][2]int{{1, 2}, {3, 4}}, spec.Matrix)
+	assert.Equal(t, [][][]string{{{"a", "b"}, {"c"}}, {{"d"}}}, spec.Triples)
+}
+
+func TestNestedGroupSeparators(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Global [][]string
+		Field  map[string][]string `groups:"/"`
+	}
+	options := DefaultOptions()
+	options.GroupSeparators = []string{"&"}
+	options.Source = MapSource{"GLOBAL": "a,b&c", "FIELD": "x:1,2/y:3"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, spec.Global)
+	assert.Equal(t, map[string][]string{"x": {"1", "2"}, "y": {"3"}}, spec.Field)
+
+	options.GroupSeparators = nil
+	options.Source = MapSource{"GLOBAL": "a,b;c"}
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), "GLOBAL: missing group separator for nesting level 1 of 2")
+}
+
+func TestNestedQuotingAndJSON(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Quoted [][]string
+		JSON   map[string][]int
+	}
+	options := DefaultOptions()
+	options.Slice.Quoting = true
+	options.Slice.JSON = JSONSyntaxAuto
+	options.Map.JSON = JSONSyntaxAuto
+	options.Source = MapSource{
+		"QUOTED": `"a;b",c\,d;e`,
+		"JSON":   `{"a":[1,2],"b":[]}`,
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, [][]string{{"a;b", "c,d"}, {"e"}}, spec.Quoted)
+	assert.Equal(t, map[string][]int{"a": {1, 2}, "b": {}}, spec.JSON)
+}
@@ -0,0 +1,59 @@
+package envconfig
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var builtinDecoders = map[reflect.Type]DecoderFunc{
+	reflect.TypeOf(url.URL{}): func(value string) (any, error) {
+		parsed, err := url.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		return *parsed, nil
+	},
+	reflect.TypeOf(net.IPNet{}): func(value string) (any, error) {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+		return *network, nil
+	},
+	reflect.TypeOf((*time.Location)(nil)): func(value string) (any, error) {
+		return time.LoadLocation(value)
+	},
+}
+
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+func (h HostPort) String() string {
+	return net.JoinHostPort(h.Host, strconv.FormatUint(uint64(h.Port), 10))
+}
+
+func (h HostPort) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *HostPort) UnmarshalText(text []byte) error {
+	host, port, err := net.SplitHostPort(string(text))
+	if err != nil {
+		return err
+	}
+
+	number, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return fmt.Errorf("invalid port %q: %w", port, err)
+	}
+
+	h.Host = host
+	h.Port = uint16(number)
+	return nil
+}
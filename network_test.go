@@ -0,0 +1,108 @@
+package envconfig
+
+import (
+	"net"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestNetworkFields(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Endpoint     *url.URL
+		Callback     url.URL
+		IP           net.IP
+		Network      net.IPNet
+		Addr         netip.Addr
+		Prefix       netip.Prefix
+		AddrPort     netip.AddrPort
+		Listen       HostPort
+		Location     *time.Location
+		Pattern      *regexp.Regexp
+		PatternValue regexp.Regexp
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"ENDPOINT":      "https://api.example.com/v1?x=1",
+		"CALLBACK":      "http://localhost:8080/cb",
+		"IP":            "192.168.1.1",
+		"NETWORK":       "10.0.0.0/8",
+		"ADDR":          "::1",
+		"PREFIX":        "fd00::/64",
+		"ADDR_PORT":     "127.0.0.1:5432",
+		"LISTEN":        "[::]:8443",
+		"LOCATION":      "Europe/Berlin",
+		"PATTERN":       "^a+b$",
+		"PATTERN_VALUE": "[0-9]+",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "api.example.com", spec.Endpoint.Host)
+	assert.Equal(t, "1", spec.Endpoint.Query().Get("x"))
+	assert.Equal(t, "/cb", spec.Callback.Path)
+	assert.Equal(t, net.ParseIP("192.168.1.1"), spec.IP)
+	assert.Equal(t, "10.0.0.0/8", spec.Network.String())
+	assert.Equal(t, netip.MustParseAddr("::1"), spec.Addr)
+	assert.Equal(t, netip.MustParsePrefix("fd00::/64"), spec.Prefix)
+	assert.Equal(t, netip.MustParseAddrPort("127.0.0.1:5432"), spec.AddrPort)
+	assert.Equal(t, HostPort{Host: "::", Port: 8443}, spec.Listen)
+	assert.Equal(t, "Europe/Berlin", spec.Location.String())
+	assert.True(t, spec.Pattern.MatchString("aab"))
+	assert.True(t, spec.PatternValue.MatchString("42"))
+}
+
+func TestNetworkCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		AllowList []net.IPNet
+		Prefixes  []netip.Prefix
+		Upstreams map[string]*url.URL
+		Zones     map[string]*time.Location
+		Peers     []HostPort
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"ALLOW_LIST":     "10.0.0.0/8, 192.168.0.0/16",
+		"PREFIXES_0":     "fd00::/8",
+		"UPSTREAMS_AUTH": "http://auth:9000",
+		"ZONES_EU":       "Europe/Paris",
+		"PEERS":          "a:1,b:2",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Len(t, spec.AllowList, 2)
+	assert.Equal(t, "192.168.0.0/16", spec.AllowList[1].String())
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("fd00::/8")}, spec.Prefixes)
+	assert.Equal(t, "auth:9000", spec.Upstreams["AUTH"].Host)
+	assert.Equal(t, "Europe/Paris", spec.Zones["EU"].String())
+	assert.Equal(t, []HostPort{{"a", 1}, {"b", 2}}, spec.Peers)
+}
+
+func TestNetworkFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Network  net.IPNet
+		Listen   HostPort
+		Location *time.Location
+	}
+
+	for name, value := range map[string]string{
+		"NETWORK":  "10.0.0.0",
+		"LISTEN":   "localhost:http",
+		"LOCATION": "Mars/Olympus",
+	} {
+		options := DefaultOptions()
+		options.Source = MapSource{name: value}
+		assert.Error(t, InitWithOptions(&TestSpec{}, options), name)
+	}
+}
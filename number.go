@@ -0,0 +1,46 @@
+package envconfig
+
+import (
+	"strconv"
+	"strings"
+)
+
+func (o Options) parseInt(value string, bitSize int) (int64, error) {
+	if o.Quantities {
+		return parseQuantityInt(value, bitSize)
+	}
+	if !o.NumberLiterals {
+		return strconv.ParseInt(value, 10, bitSize)
+	}
+	if isLegacyOctal(value) {
+		return strconv.ParseInt(strings.ReplaceAll(value, "_", ""), 10, bitSize)
+	}
+	return strconv.ParseInt(value, 0, bitSize)
+}
+
+func (o Options) parseUint(value string, bitSize int) (uint64, error) {
+	if o.Quantities {
+		return parseQuantityUint(value, bitSize)
+	}
+	if !o.NumberLiterals {
+		return strconv.ParseUint(value, 10, bitSize)
+	}
+	if isLegacyOctal(value) {
+		return strconv.ParseUint(strings.ReplaceAll(value, "_", ""), 10, bitSize)
+	}
+	return strconv.ParseUint(value, 0, bitSize)
+}
+
+func (o Options) numberLiteral(value string) string {
+	if !o.NumberLiterals {
+		return value
+	}
+	return strings.ReplaceAll(value, "_", "")
+}
+
+// isLegacyOctal reports whether value has a leading zero without a base
+// prefix, which strconv would otherwise parse as octal.
+func isLegacyOctal(value string) bool {
+	digits := strings.TrimLeft(value, "+-")
+	return len(digits) > 1 && digits[0] == '0' && (digits[1] >= '0' && digits[1] <= '9' || digits[1] == '_')
+}
@@ -0,0 +1,117 @@
+package envconfig
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestIntegerOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Int8    int8
+		Uint16  uint16
+		Int32   int32
+		Float32 float32
+		Complex complex64
+		Slice   []int8
+		Map     map[string]int16
+	}
+
+	testCases := map[string]string{
+		"INT8":    "300",
+		"UINT16":  "70000",
+		"INT32":   "-2147483649",
+		"FLOAT32": "1e39",
+		"COMPLEX": "1e39+1i",
+		"SLICE":   "1,200",
+		"MAP_KEY": "40000",
+	}
+
+	for name, value := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.Source = MapSource{name: value}
+
+			err := InitWithOptions(&TestSpec{}, options)
+			assert.True(t, errors.Is(err, strconv.ErrRange))
+		})
+	}
+}
+
+func TestIntegerBoundaries(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Int8   int8
+		Uint8  uint8
+		Int64  int64
+		Uint64 uint64
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"INT8":   "-128",
+		"UINT8":  "255",
+		"INT64":  "-9223372036854775808",
+		"UINT64": "18446744073709551615",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, int8(-128), spec.Int8)
+	assert.Equal(t, uint8(255), spec.Uint8)
+	assert.Equal(t, int64(-9223372036854775808), spec.Int64)
+	assert.Equal(t, uint64(18446744073709551615), spec.Uint64)
+}
+
+func TestNumberLiterals(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hex     int
+		Octal   uint32
+		Binary  int8
+		Grouped int64
+		Zip     int
+		Float   float64
+	}
+	source := MapSource{
+		"HEX":     "0xFF",
+		"OCTAL":   "0o755",
+		"BINARY":  "-0b101",
+		"GROUPED": "1_000_000",
+		"ZIP":     "01234",
+		"FLOAT":   "1_000.5",
+	}
+
+	options := DefaultOptions()
+	options.Source = source
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+
+	options.NumberLiterals = true
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, 255, spec.Hex)
+	assert.Equal(t, uint32(0o755), spec.Octal)
+	assert.Equal(t, int8(-5), spec.Binary)
+	assert.Equal(t, int64(1000000), spec.Grouped)
+	assert.Equal(t, 1234, spec.Zip)
+	assert.Equal(t, 1000.5, spec.Float)
+}
+
+func TestNumberLiteralsOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Port uint16
+	}
+	options := DefaultOptions()
+	options.NumberLiterals = true
+	options.Source = MapSource{"PORT": "0x1_0000"}
+
+	err := InitWithOptions(&TestSpec{}, options)
+	assert.True(t, errors.Is(err, strconv.ErrRange))
+}
@@ -0,0 +1,121 @@
+package envconfig
+
+import (
+	"errors"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+var quantityPattern = regexp.MustCompile(`^\s*([+-]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][+-]?[0-9]+)?)\s*([A-Za-z]*)\s*$`)
+
+var quantitySuffixes = map[string]*big.Rat{
+	"":    big.NewRat(1, 1),
+	"B":   big.NewRat(1, 1),
+	"m":   big.NewRat(1, 1000),
+	"k":   decimalUnit(1),
+	"K":   decimalUnit(1),
+	"kB":  decimalUnit(1),
+	"KB":  decimalUnit(1),
+	"M":   decimalUnit(2),
+	"MB":  decimalUnit(2),
+	"G":   decimalUnit(3),
+	"GB":  decimalUnit(3),
+	"T":   decimalUnit(4),
+	"TB":  decimalUnit(4),
+	"P":   decimalUnit(5),
+	"PB":  decimalUnit(5),
+	"E":   decimalUnit(6),
+	"EB":  decimalUnit(6),
+	"Ki":  binaryUnit(1),
+	"KiB": binaryUnit(1),
+	"Mi":  binaryUnit(2),
+	"MiB": binaryUnit(2),
+	"Gi":  binaryUnit(3),
+	"GiB": binaryUnit(3),
+	"Ti":  binaryUnit(4),
+	"TiB": binaryUnit(4),
+	"Pi":  binaryUnit(5),
+	"PiB": binaryUnit(5),
+	"Ei":  binaryUnit(6),
+	"EiB": binaryUnit(6),
+}
+
+func decimalUnit(power int64) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(1000), big.NewInt(power), nil))
+}
+
+func binaryUnit(power int64) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(10*power)))
+}
+
+type ByteSize uint64
+
+func (s ByteSize) String() string {
+	return strconv.FormatUint(uint64(s), 10) + "B"
+}
+
+func (s ByteSize) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *ByteSize) UnmarshalText(text []byte) error {
+	size, err := parseQuantityUint(string(text), 64)
+	if err != nil {
+		return err
+	}
+	*s = ByteSize(size)
+	return nil
+}
+
+func parseQuantity(value string) (*big.Int, error) {
+	match := quantityPattern.FindStringSubmatch(value)
+	if match == nil {
+		return nil, quantityError(value, strconv.ErrSyntax)
+	}
+
+	unit, ok := quantitySuffixes[match[2]]
+	if !ok {
+		return nil, quantityError(value, errors.New("unknown unit "+strconv.Quote(match[2])))
+	}
+
+	number, ok := new(big.Rat).SetString(match[1])
+	if !ok {
+		return nil, quantityError(value, strconv.ErrSyntax)
+	}
+
+	number.Mul(number, unit)
+	if !number.IsInt() {
+		return nil, quantityError(value, errors.New("not a whole number"))
+	}
+	return number.Num(), nil
+}
+
+func parseQuantityInt(value string, bitSize int) (int64, error) {
+	number, err := parseQuantity(value)
+	if err != nil {
+		return 0, err
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bitSize-1))
+	if number.Cmp(limit) >= 0 || number.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return 0, quantityError(value, strconv.ErrRange)
+	}
+	return number.Int64(), nil
+}
+
+func parseQuantityUint(value string, bitSize int) (uint64, error) {
+	number, err := parseQuantity(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if number.Sign() < 0 || number.BitLen() > bitSize {
+		return 0, quantityError(value, strconv.ErrRange)
+	}
+	return number.Uint64(), nil
+}
+
+func quantityError(value string, err error) error {
+	return &strconv.NumError{Func: "ParseQuantity", Num: value, Err: err}
+}
@@ -0,0 +1,120 @@
+package envconfig
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestByteSize(t *testing.T) {
+	testCases := map[string]ByteSize{
+		"100":    100,
+		"512MiB": 512 << 20,
+		"10k":    10000,
+		"1.5G":   1500000000,
+		"2Gi":    2 << 30,
+		"1.5KiB": 1536,
+		"64 KB":  64000,
+		"1e3":    1000,
+		"16EiB":  0,
+	}
+
+	for value, expected := range testCases {
+		var size ByteSize
+		err := size.UnmarshalText([]byte(value))
+		if value == "16EiB" {
+			assert.True(t, errors.Is(err, strconv.ErrRange))
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, expected, size)
+	}
+}
+
+func TestByteSizeInvalid(t *testing.T) {
+	for _, value := range []string{"", "abc", "1.5B", "10XB", "-1Ki", "100m"} {
+		var size ByteSize
+		assert.Error(t, size.UnmarshalText([]byte(value)))
+	}
+}
+
+func TestByteSizeFields(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Memory  ByteSize
+		Buffers []ByteSize
+		Limits  map[string]ByteSize
+		Pointer *ByteSize
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"MEMORY":         "512MiB",
+		"BUFFERS":        "4Ki, 1M",
+		"LIMITS_UPLOADS": "10G",
+		"POINTER":        "1k",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, ByteSize(512<<20), spec.Memory)
+	assert.Equal(t, []ByteSize{4096, 1000000}, spec.Buffers)
+	assert.Equal(t, map[string]ByteSize{"UPLOADS": 10000000000}, spec.Limits)
+	assert.Equal(t, ByteSize(1000), *spec.Pointer)
+}
+
+func TestQuantityIntegerFields(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Limit    int64
+		Replicas []uint16
+		Millis   map[string]int32
+		Plain    int `quantity:"false"`
+	}
+	options := DefaultOptions()
+	options.Quantities = true
+	options.Source = MapSource{
+		"LIMIT":       "1.5Gi",
+		"REPLICAS_0":  "70k",
+		"MILLIS_CPU":  "500",
+		"MILLIS_CORE": "2k",
+		"PLAIN":       "42",
+	}
+
+	spec := TestSpec{}
+	err := InitWithOptions(&spec, options)
+	assert.True(t, errors.Is(err, strconv.ErrRange))
+
+	options.Source = MapSource{
+		"LIMIT":       "1.5Gi",
+		"REPLICAS_0":  "200",
+		"MILLIS_CORE": "2k",
+		"PLAIN":       "42",
+	}
+	spec = TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, int64(1610612736), spec.Limit)
+	assert.Equal(t, []uint16{200}, spec.Replicas)
+	assert.Equal(t, map[string]int32{"CORE": 2000}, spec.Millis)
+	assert.Equal(t, 42, spec.Plain)
+}
+
+func TestQuantityFieldTag(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Limit int64 `quantity:"true"`
+		Other int64
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"LIMIT": "2Mi", "OTHER": "2Mi"}
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+
+	options.Source = MapSource{"LIMIT": "2Mi", "OTHER": "2"}
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, int64(2<<20), spec.Limit)
+}
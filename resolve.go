@@ -0,0 +1,108 @@
+package envconfig
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+type Resolver interface {
+	Resolve(ctx context.Context, reference string) (string, error)
+}
+
+type ResolverFunc func(ctx context.Context, reference string) (string, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, reference string) (string, error) {
+	return f(ctx, reference)
+}
+
+var referenceScheme = regexp.MustCompile("^([A-Za-z][A-Za-z0-9+.-]*):")
+
+func DefaultResolvers() map[string]Resolver {
+	return map[string]Resolver{
+		"file":   ResolverFunc(resolveFile),
+		"base64": ResolverFunc(resolveBase64),
+		"env":    ResolverFunc(resolveEnv),
+	}
+}
+
+type sourceKey struct{}
+
+func SourceFromContext(ctx context.Context) Source {
+	if source, ok := ctx.Value(sourceKey{}).(Source); ok {
+		return source
+	}
+	return EnvironmentSource()
+}
+
+func (o Options) resolve(ctx context.Context, source Source, name, value string) (string, error) {
+	match := referenceScheme.FindStringSubmatch(value)
+	if match == nil {
+		return value, nil
+	}
+
+	resolver, ok := o.Resolvers[strings.ToLower(match[1])]
+	if !ok {
+		return value, nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", &VariableError{Name: name, Err: err}
+	}
+
+	resolved, err := resolver.Resolve(context.WithValue(ctx, sourceKey{}, source), value)
+	if err != nil {
+		return "", &VariableError{Name: name, Err: err}
+	}
+	return resolved, nil
+}
+
+func referenceBody(reference string) string {
+	_, body, _ := strings.Cut(reference, ":")
+	return strings.TrimPrefix(body, "//")
+}
+
+func resolveFile(_ context.Context, reference string) (string, error) {
+	location, err := url.Parse(reference)
+	if err != nil {
+		return "", err
+	}
+
+	path := location.Path
+	if location.Opaque != "" {
+		path = location.Opaque
+	} else if location.Host != "" {
+		path = location.Host + path
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func resolveBase64(_ context.Context, reference string) (string, error) {
+	body := referenceBody(reference)
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		decoded, err = base64.RawStdEncoding.DecodeString(body)
+	}
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 reference: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func resolveEnv(ctx context.Context, reference string) (string, error) {
+	name := referenceBody(reference)
+	value, ok := SourceFromContext(ctx).Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("referenced variable %s is not set", name)
+	}
+	return value, nil
+}
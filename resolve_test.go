@@ -0,0 +1,152 @@
+package envconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestResolveDefaultSchemes(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		SecretKey string
+		Token     string
+		Alias     string
+		Plain     string
+		MapField  map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"SECRET_KEY":    "file://" + writeSecret(t, "api-key\n"),
+		"TOKEN":         "base64:c2VjcmV0",
+		"ALIAS":         "env://TARGET",
+		"TARGET":        "aliased",
+		"PLAIN":         "http://example.com",
+		"MAP_FIELD_KEY": "base64:ZW50cnk=",
+	}
+	options.Resolvers = DefaultResolvers()
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "api-key", spec.SecretKey)
+	assert.Equal(t, "secret", spec.Token)
+	assert.Equal(t, "aliased", spec.Alias)
+	assert.Equal(t, "http://example.com", spec.Plain)
+	assert.Equal(t, map[string]string{"KEY": "entry"}, spec.MapField)
+}
+
+func TestResolveDisabled(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Token string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TOKEN": "base64:c2VjcmV0"}
+	options.Resolvers = nil
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "base64:c2VjcmV0", spec.Token)
+}
+
+func TestResolveCustomScheme(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/secret/db" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, "s3cr3t")
+	}))
+	defer server.Close()
+
+	vault := ResolverFunc(func(ctx context.Context, reference string) (string, error) {
+		path := strings.TrimPrefix(reference, "vault://")
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/"+path, nil)
+		if err != nil {
+			return "", err
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("vault: %s", response.Status)
+		}
+		body, err := io.ReadAll(response.Body)
+		return string(body), err
+	})
+
+	type TestSpec struct {
+		Password string
+		Missing  string
+	}
+
+	options := DefaultOptions()
+	options.Source = MapSource{"PASSWORD": "vault://secret/db"}
+	options.Resolvers = DefaultResolvers()
+	options.Resolvers["vault"] = vault
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "s3cr3t", spec.Password)
+
+	options.Source = MapSource{"MISSING": "vault://secret/missing"}
+	err := InitWithOptions(&TestSpec{}, options)
+	var variableErr *VariableError
+	assert.True(t, errors.As(err, &variableErr))
+	assert.Equal(t, "MISSING", variableErr.Name)
+}
+
+func TestResolveContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Token string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TOKEN": "slow:value"}
+	options.Resolvers = DefaultResolvers()
+	options.Resolvers["slow"] = ResolverFunc(func(ctx context.Context, reference string) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+			return reference, nil
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := InitWithContext(ctx, &TestSpec{}, options)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.EqualError(t, err, "TOKEN: context deadline exceeded")
+}
+
+func TestResolveIgnoresUnrelated(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Labels map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"UNRELATED": "file:///does/not/exist", "LABELS_A": "x"}
+	options.Resolvers = DefaultResolvers()
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]string{"A": "x"}, spec.Labels)
+}
@@ -0,0 +1,62 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+)
+
+func isEmptyStruct(spec reflect.Type) bool {
+	spec = indirect(spec)
+	return spec.Kind() == reflect.Struct && spec.NumField() == 0
+}
+
+func (o Options) setMember(spec reflect.Type, entry string) (string, bool, error) {
+	if !isEmptyStruct(spec) {
+		if indirect(spec).Kind() != reflect.Bool {
+			return "", false, nil
+		}
+		if tokens := splitGroups(entry, o.Map.KeyValueSeparator, 2, o.Map.Quoting); len(tokens) > 1 {
+			return "", false, nil
+		}
+	}
+
+	if !o.Map.Quoting {
+		return entry, true, nil
+	}
+	member, err := unquote(entry)
+	return member, true, err
+}
+
+func (o Options) setMapEntry(target, key, value reflect.Value) error {
+	if o.Map.RejectDuplicates && target.MapIndex(key).IsValid() {
+		return fmt.Errorf("duplicate key %q", fmt.Sprint(key.Interface()))
+	}
+	target.SetMapIndex(key, value)
+	return nil
+}
+
+func (o Options) setSetMember(target reflect.Value, member, value string) error {
+	if target.Kind() != reflect.Map {
+		return fmt.Errorf("invalid type: expected %s but got %s", reflect.Map, target.Kind())
+	}
+
+	key := reflect.New(target.Type().Key()).Elem()
+	if err := o.forKey().setPrimitive(key, member); err != nil {
+		return err
+	}
+
+	present, err := o.parseBool(value)
+	if err != nil {
+		return err
+	}
+
+	if target.IsNil() {
+		target.Set(reflect.MakeMap(target.Type()))
+	}
+	if present {
+		target.SetMapIndex(key, reflect.New(target.Type().Elem()).Elem())
+	} else {
+		target.SetMapIndex(key, reflect.Value{})
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package envconfig
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestSetFromList(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Features map[string]struct{}
+		Toggles  map[string]bool
+		Hosts    map[HostPort]struct{}
+		Ports    *map[uint16]struct{}
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"FEATURES": "a, b,a",
+		"TOGGLES":  "cache,trace:false",
+		"HOSTS":    "db:5432,cache:6379",
+		"PORTS":    "80,443",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]struct{}{"a": {}, "b": {}}, spec.Features)
+	assert.Equal(t, map[string]bool{"cache": true, "trace": false}, spec.Toggles)
+	assert.Equal(t, map[HostPort]struct{}{{"db", 5432}: {}, {"cache", 6379}: {}}, spec.Hosts)
+	assert.Equal(t, map[uint16]struct{}{80: {}, 443: {}}, *spec.Ports)
+}
+
+func TestSetRejectDuplicates(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Features map[string]struct{}
+		Labels   map[string]string
+	}
+	options := DefaultOptions()
+	options.Map.RejectDuplicates = true
+
+	options.Source = MapSource{"FEATURES": "a,b,a"}
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), `FEATURES: duplicate key "a"`)
+
+	options.Source = MapSource{"LABELS": "team:core,team:edge"}
+	assert.EqualError(t, InitWithOptions(&TestSpec{}, options), `LABELS: duplicate key "team"`)
+}
+
+func TestSetTemplates(t *testing.T) {
+	t.Parallel()
+
+	type UserSpec struct {
+		Roles map[string]struct{}
+	}
+	type TestSpec struct {
+		Features map[string]struct{}
+		Users    map[string]UserSpec
+		Groups   []map[string]struct{}
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"FEATURES_cache":  "true",
+		"FEATURES_trace":  "false",
+		"USERS_bob_ROLES": "admin,dev",
+		"GROUPS":          "x,y;z",
+	}
+
+	spec := TestSpec{Features: map[string]struct{}{"trace": {}}}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, map[string]struct{}{"cache": {}}, spec.Features)
+	assert.Equal(t, map[string]UserSpec{"bob": {Roles: map[string]struct{}{"admin": {}, "dev": {}}}}, spec.Users)
+	assert.Equal(t, []map[string]struct{}{{"x": {}, "y": {}}, {"z": {}}}, spec.Groups)
+}
+
+func TestSetFlags(t *testing.T) {
+	type TestSpec struct {
+		Features map[string]struct{}
+	}
+
+	spec := TestSpec{}
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	assert.NoError(t, BindFlags(flags, &spec, DefaultOptions()))
+	assert.NoError(t, flags.Parse([]string{"-features", "a", "-features", "b"}))
+	assert.Equal(t, map[string]struct{}{"a": {}, "b": {}}, spec.Features)
+}
@@ -0,0 +1,117 @@
+package envconfig
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+type Source interface {
+	Lookup(name string) (string, bool)
+	Keys() []string
+}
+
+type environmentSource struct{}
+
+func EnvironmentSource() Source {
+	return environmentSource{}
+}
+
+func (environmentSource) Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (environmentSource) Keys() []string {
+	environ := os.Environ()
+	keys := make([]string, 0, len(environ))
+	for _, variable := range environ {
+		key, _, _ := strings.Cut(variable, "=")
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+type MapSource map[string]string
+
+func (s MapSource) Lookup(name string) (string, bool) {
+	value, ok := s[name]
+	return value, ok
+}
+
+func (s MapSource) Keys() []string {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func EnvironSource(environ []string) MapSource {
+	variables := make(MapSource, len(environ))
+	for _, variable := range environ {
+		key, value, ok := strings.Cut(variable, "=")
+		if ok && key != "" {
+			variables[key] = value
+		}
+	}
+	return variables
+}
+
+type OriginSource interface {
+	Source
+	Origin(name string) (string, bool)
+}
+
+type rankedSource interface {
+	rank(name string) int
+}
+
+type Layer struct {
+	Name   string
+	Source Source
+}
+
+type LayeredSource []Layer
+
+func Layers(layers ...Layer) LayeredSource {
+	return layers
+}
+
+func (s LayeredSource) Lookup(name string) (string, bool) {
+	if index := s.rank(name); index >= 0 {
+		return s[index].Source.Lookup(name)
+	}
+	return "", false
+}
+
+func (s LayeredSource) Keys() []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, layer := range s {
+		for _, key := range layer.Source.Keys() {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (s LayeredSource) Origin(name string) (string, bool) {
+	if index := s.rank(name); index >= 0 {
+		return s[index].Name, true
+	}
+	return "", false
+}
+
+func (s LayeredSource) rank(name string) int {
+	for i := len(s) - 1; i >= 0; i-- {
+		if _, ok := s[i].Source.Lookup(name); ok {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,181 @@
+package envconfig
+
+import (
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestMapSourceField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TEST_FIELD": "test"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "test", spec.TestField)
+}
+
+func TestMapSourceTemplate(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		MapField map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"MAP_FIELD_MAP_KEY": "test"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "test", spec.MapField["MAP_KEY"])
+}
+
+func TestMapSourceIgnoresEnvironment(t *testing.T) {
+	type TestSpec struct {
+		TestField string
+	}
+	withEnv("TEST_FIELD", "test", func() {
+		options := DefaultOptions()
+		options.Source = MapSource{}
+
+		spec := TestSpec{}
+		assert.NoError(t, InitWithOptions(&spec, options))
+		assert.Equal(t, "", spec.TestField)
+	})
+}
+
+func TestMapSourceKeysAreSorted(t *testing.T) {
+	source := MapSource{"B": "2", "A": "1", "C": "3"}
+	assert.Equal(t, []string{"A", "B", "C"}, source.Keys())
+}
+
+func TestEnvironmentSource(t *testing.T) {
+	withEnv("TEST_FIELD", "test", func() {
+		source := EnvironmentSource()
+
+		value, ok := source.Lookup("TEST_FIELD")
+		assert.True(t, ok)
+		assert.Equal(t, "test", value)
+		assert.Contains(t, source.Keys(), "TEST_FIELD")
+	})
+}
+
+func TestLayeredSource(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		First    string
+		Second   string
+		Third    string
+		MapField map[string]string
+	}
+
+	options := DefaultOptions()
+	options.Source = Layers(
+		Layer{Name: "defaults", Source: MapSource{"FIRST": "defaults", "SECOND": "defaults", "THIRD": "defaults", "MAP_FIELD_KEY": "defaults"}},
+		Layer{Name: "dotenv", Source: MapSource{"SECOND": "dotenv", "MAP_FIELD_KEY": "dotenv"}},
+		Layer{Name: "overrides", Source: MapSource{"THIRD": "overrides"}},
+	)
+
+	origins := make(map[string]string)
+	options.Report = func(name, origin string) {
+		origins[name] = origin
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "defaults", spec.First)
+	assert.Equal(t, "dotenv", spec.Second)
+	assert.Equal(t, "overrides", spec.Third)
+	assert.Equal(t, "dotenv", spec.MapField["KEY"])
+	assert.Equal(t, map[string]string{
+		"FIRST":         "defaults",
+		"SECOND":        "dotenv",
+		"THIRD":         "overrides",
+		"MAP_FIELD_KEY": "dotenv",
+	}, origins)
+}
+
+func TestLayeredSourcePrecedenceAcrossNames(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+	}
+
+	reports := make([]string, 0)
+	options := DefaultOptions()
+	options.Report = func(name, origin string) {
+		reports = append(reports, name+" "+origin)
+	}
+	options.Source = Layers(
+		Layer{Name: "low", Source: MapSource{"TestField": "low"}},
+		Layer{Name: "high", Source: MapSource{"TEST_FIELD": "high"}},
+	)
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "high", spec.TestField)
+	assert.Equal(t, []string{"TEST_FIELD high"}, reports)
+
+	reports = reports[:0]
+	options.Source = Layers(
+		Layer{Name: "low", Source: MapSource{"TEST_FIELD": "low"}},
+		Layer{Name: "high", Source: MapSource{"TestField": "high"}},
+	)
+
+	spec = TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, "high", spec.TestField)
+	assert.Equal(t, []string{"TestField high"}, reports)
+}
+
+func TestInitFromMap(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField  string
+		SliceField []int
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitFromMap(&spec, map[string]string{"TEST_FIELD": "test", "SLICE_FIELD_1": "2"}))
+	assert.Equal(t, "test", spec.TestField)
+	assert.Equal(t, []int{0, 2}, spec.SliceField)
+}
+
+func TestInitFromEnviron(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		TestField string
+		MapField  map[string]string
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitFromEnviron(&spec, []string{"TEST_FIELD=first", "MAP_FIELD_KEY=a=b", "INVALID", "TEST_FIELD=second"}))
+	assert.Equal(t, "second", spec.TestField)
+	assert.Equal(t, map[string]string{"KEY": "a=b"}, spec.MapField)
+}
+
+func TestInitFromMapConcurrently(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Tenant string
+	}
+
+	for _, tenant := range []string{"a", "b", "c", "d"} {
+		t.Run(tenant, func(t *testing.T) {
+			t.Parallel()
+
+			spec := TestSpec{}
+			assert.NoError(t, InitFromMap(&spec, map[string]string{"TENANT": tenant}))
+			assert.Equal(t, tenant, spec.Tenant)
+		})
+	}
+}
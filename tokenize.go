@@ -0,0 +1,151 @@
+package envconfig
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func (o Options) parseElements(spec reflect.Type, value string, groups []string) ([]string, error) {
+	if o.Slice.JSON.matches(value, '[', ']') {
+		return jsonElements(spec, value)
+	}
+	if len(groups) > 0 {
+		return splitGroups(value, groups[0], -1, o.Slice.Quoting), nil
+	}
+	return splitTokens(value, o.Slice.ElementSeparator, -1, o.Slice.Quoting)
+}
+
+func (o Options) parseEntries(spec reflect.Type, value string, groups []string) ([][2]string, error) {
+	if o.Map.JSON.matches(value, '{', '}') {
+		return jsonEntries(spec, value)
+	}
+
+	separator := o.Map.EntrySeparator
+	if len(groups) > 0 {
+		separator = groups[0]
+	}
+
+	entries := make([][2]string, 0)
+	for _, pair := range splitGroups(value, separator, -1, o.Map.Quoting) {
+		if member, ok, err := o.setMember(spec, pair); ok || err != nil {
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, [2]string{member, "true"})
+			continue
+		}
+
+		key, element, err := o.splitEntry(pair, len(groups) > 0)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, [2]string{key, element})
+	}
+	return entries, nil
+}
+
+func (o Options) splitEntry(entry string, nested bool) (string, string, error) {
+	tokens := splitGroups(entry, o.Map.KeyValueSeparator, 2, o.Map.Quoting)
+	if len(tokens) < 2 {
+		return "", "", fmt.Errorf("invalid map entry %q: missing %q", entry, o.Map.KeyValueSeparator)
+	}
+	if !o.Map.Quoting {
+		return tokens[0], tokens[1], nil
+	}
+
+	key, err := unquote(tokens[0])
+	if err != nil || nested {
+		return key, tokens[1], err
+	}
+	element, err := unquote(tokens[1])
+	return key, element, err
+}
+
+func splitTokens(value, separator string, n int, quoting bool) ([]string, error) {
+	tokens := splitGroups(value, separator, n, quoting)
+	if !quoting {
+		return tokens, nil
+	}
+
+	for i, token := range tokens {
+		unquoted, err := unquote(token)
+		if err != nil {
+			return nil, err
+		}
+		tokens[i] = unquoted
+	}
+	return tokens, nil
+}
+
+func splitGroups(value, separator string, n int, quoting bool) []string {
+	var tokens []string
+	if quoting {
+		tokens = splitQuoted(value, separator, n)
+	} else {
+		tokens = strings.SplitN(value, separator, n)
+	}
+
+	for i, token := range tokens {
+		tokens[i] = strings.TrimSpace(token)
+	}
+	return tokens
+}
+
+func splitQuoted(value, separator string, n int) []string {
+	tokens := make([]string, 0)
+	start := 0
+	quoted := false
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\':
+			i++
+		case value[i] == '"':
+			quoted = !quoted
+		case !quoted && separator != "" && strings.HasPrefix(value[i:], separator) && (n < 0 || len(tokens) < n-1):
+			tokens = append(tokens, value[start:i])
+			start = i + len(separator)
+			i = start - 1
+		}
+	}
+	return append(tokens, value[start:])
+}
+
+func unquote(token string) (string, error) {
+	token = strings.TrimSpace(token)
+
+	var result strings.Builder
+	quoted := false
+	for i := 0; i < len(token); i++ {
+		switch c := token[i]; {
+		case c == '\\':
+			if i+1 >= len(token) {
+				return "", fmt.Errorf("invalid escape at end of %q", token)
+			}
+			i++
+			result.WriteByte(token[i])
+		case c == '"' && !quoted:
+			if i != 0 {
+				return "", fmt.Errorf("unexpected quote in %q", token)
+			}
+			quoted = true
+		case c == '"':
+			if i+1 < len(token) && token[i+1] == '"' {
+				result.WriteByte('"')
+				i++
+				continue
+			}
+			if i != len(token)-1 {
+				return "", fmt.Errorf("unexpected text after closing quote in %q", token)
+			}
+			quoted = false
+		default:
+			result.WriteByte(c)
+		}
+	}
+
+	if quoted {
+		return "", fmt.Errorf("unterminated quote in %q", token)
+	}
+	return result.String(), nil
+}
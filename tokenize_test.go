@@ -0,0 +1,81 @@
+package envconfig
+
+import (
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+func TestQuotedCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts     []string
+		Endpoints map[string]string
+		Hostnames [2]string
+	}
+	options := DefaultOptions()
+	options.Slice.Quoting = true
+	options.Map.Quoting = true
+	options.Source = MapSource{
+		"HOSTS":     `a\,b, " c,d ", "say ""hi""", e\\f`,
+		"ENDPOINTS": `api:"http://api:8080", "db:primary":tcp\://db`,
+		"HOSTNAMES": `"", x`,
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []string{"a,b", " c,d ", `say "hi"`, `e\f`}, spec.Hosts)
+	assert.Equal(t, map[string]string{"api": "http://api:8080", "db:primary": "tcp://db"}, spec.Endpoints)
+	assert.Equal(t, [2]string{"", "x"}, spec.Hostnames)
+}
+
+func TestQuotingDisabled(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts     []string
+		Endpoints map[string]string
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"HOSTS": `"a,b"`, "ENDPOINTS": "api:http://api:8080"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []string{`"a`, `b"`}, spec.Hosts)
+	assert.Equal(t, map[string]string{"api": "http://api:8080"}, spec.Endpoints)
+}
+
+func TestMalformedCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Hosts     []string
+		Endpoints map[string]string
+	}
+
+	testCases := map[string]struct {
+		quoting  bool
+		name     string
+		value    string
+		expected string
+	}{
+		"missing separator": {false, "ENDPOINTS", "api:a,db", `ENDPOINTS: invalid map entry "db": missing ":"`},
+		"quoted separator":  {true, "ENDPOINTS", `"api:a"`, `ENDPOINTS: invalid map entry "\"api:a\"": missing ":"`},
+		"unterminated":      {true, "HOSTS", `a,"b`, `HOSTS: unterminated quote in "\"b"`},
+		"trailing text":     {true, "HOSTS", `"a"b`, `HOSTS: unexpected text after closing quote in "\"a\"b"`},
+		"inner quote":       {true, "HOSTS", `a"b"`, `HOSTS: unexpected quote in "a\"b\""`},
+		"trailing escape":   {true, "HOSTS", `a\`, `HOSTS: invalid escape at end of "a\\"`},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.Slice.Quoting = testCase.quoting
+			options.Map.Quoting = testCase.quoting
+			options.Source = MapSource{testCase.name: testCase.value}
+
+			assert.EqualError(t, InitWithOptions(&TestSpec{}, options), testCase.expected)
+		})
+	}
+}
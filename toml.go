@@ -0,0 +1,411 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type tomlParser struct {
+	input string
+	pos   int
+}
+
+func (p *tomlParser) parse() (map[string]any, error) {
+	root := make(map[string]any)
+	current := root
+
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return root, nil
+		}
+
+		var err error
+		if p.peek() == '[' {
+			current, err = p.parseHeader(root)
+		} else {
+			err = p.parseKeyValue(current)
+		}
+		if err != nil {
+			return nil, p.error(err)
+		}
+
+		p.skipSpaces()
+		if !p.eof() && p.peek() == '#' {
+			p.skipComment()
+		}
+		if !p.eof() && p.peek() != '\n' && p.peek() != '\r' {
+			return nil, p.error(fmt.Errorf("unexpected character %q", p.peek()))
+		}
+	}
+}
+
+func (p *tomlParser) parseHeader(root map[string]any) (map[string]any, error) {
+	p.pos++
+	array := !p.eof() && p.peek() == '['
+	if array {
+		p.pos++
+	}
+
+	keys, err := p.parseKey()
+	if err != nil {
+		return nil, err
+	}
+
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if !strings.HasPrefix(p.input[p.pos:], closing) {
+		return nil, fmt.Errorf("expected %q after table name", closing)
+	}
+	p.pos += len(closing)
+
+	if !array {
+		return tomlTable(root, keys)
+	}
+
+	parent, err := tomlTable(root, keys[:len(keys)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	name := keys[len(keys)-1]
+	table := make(map[string]any)
+	switch existing := parent[name].(type) {
+	case nil:
+		parent[name] = []any{table}
+	case []any:
+		parent[name] = append(existing, table)
+	default:
+		return nil, fmt.Errorf("key %q is already defined", name)
+	}
+	return table, nil
+}
+
+func (p *tomlParser) parseKeyValue(table map[string]any) error {
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+
+	if p.eof() || p.peek() != '=' {
+		return fmt.Errorf("expected '=' after key %q", strings.Join(keys, "."))
+	}
+	p.pos++
+	p.skipSpaces()
+
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	parent, err := tomlTable(table, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+
+	name := keys[len(keys)-1]
+	if _, ok := parent[name]; ok {
+		return fmt.Errorf("key %q is already defined", strings.Join(keys, "."))
+	}
+	parent[name] = value
+	return nil
+}
+
+func (p *tomlParser) parseKey() ([]string, error) {
+	keys := make([]string, 0)
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, errors.New("unexpected end of input in key")
+		}
+
+		var key string
+		var err error
+		switch p.peek() {
+		case '"':
+			key, err = p.parseBasicString()
+		case '\'':
+			key, err = p.parseLiteralString()
+		default:
+			start := p.pos
+			for !p.eof() && isTOMLBareKey(p.peek()) {
+				p.pos++
+			}
+			key = p.input[start:p.pos]
+			if key == "" {
+				err = fmt.Errorf("invalid key character %q", p.peek())
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+
+		p.skipSpaces()
+		if p.eof() || p.peek() != '.' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	if p.eof() {
+		return nil, errors.New("missing value")
+	}
+
+	switch p.peek() {
+	case '"':
+		if strings.HasPrefix(p.input[p.pos:], `"""`) {
+			return p.parseMultiline(`"""`)
+		}
+		return p.parseBasicString()
+	case '\'':
+		if strings.HasPrefix(p.input[p.pos:], "'''") {
+			return p.parseMultiline("'''")
+		}
+		return p.parseLiteralString()
+	case '[':
+		return p.parseArray()
+	case '{':
+		return p.parseInlineTable()
+	default:
+		start := p.pos
+		for !p.eof() && !strings.ContainsRune(" \t\r\n,]}#", rune(p.peek())) {
+			p.pos++
+		}
+		if start == p.pos {
+			return nil, fmt.Errorf("unexpected character %q", p.peek())
+		}
+		return p.input[start:p.pos], nil
+	}
+}
+
+func (p *tomlParser) parseArray() (any, error) {
+	p.pos++
+	values := make([]any, 0)
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, errors.New("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return values, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skipBlank()
+		if p.eof() {
+			return nil, errors.New("unterminated array")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("expected ',' or ']' in array but got %q", p.peek())
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (any, error) {
+	p.pos++
+	table := make(map[string]any)
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			return nil, errors.New("unterminated inline table")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return table, nil
+		}
+
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if p.eof() {
+			return nil, errors.New("unterminated inline table")
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, fmt.Errorf("expected ',' or '}' in inline table but got %q", p.peek())
+		}
+	}
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.pos++
+	var value strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		switch c {
+		case '"':
+			p.pos++
+			return value.String(), nil
+		case '\n':
+			return "", errors.New("unterminated string")
+		case '\\':
+			p.pos++
+			if err := p.parseEscape(&value); err != nil {
+				return "", err
+			}
+			continue
+		default:
+			value.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", errors.New("unterminated string")
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.pos++
+	start := p.pos
+	for !p.eof() {
+		switch p.peek() {
+		case '\'':
+			value := p.input[start:p.pos]
+			p.pos++
+			return value, nil
+		case '\n':
+			return "", errors.New("unterminated string")
+		}
+		p.pos++
+	}
+	return "", errors.New("unterminated string")
+}
+
+func (p *tomlParser) parseMultiline(delimiter string) (string, error) {
+	p.pos += len(delimiter)
+	if strings.HasPrefix(p.input[p.pos:], "\r\n") {
+		p.pos += 2
+	} else if strings.HasPrefix(p.input[p.pos:], "\n") {
+		p.pos++
+	}
+
+	var value strings.Builder
+	for !p.eof() {
+		if strings.HasPrefix(p.input[p.pos:], delimiter) {
+			p.pos += len(delimiter)
+			return value.String(), nil
+		}
+		if delimiter == `"""` && p.peek() == '\\' {
+			p.pos++
+			if err := p.parseEscape(&value); err != nil {
+				return "", err
+			}
+			continue
+		}
+		value.WriteByte(p.peek())
+		p.pos++
+	}
+	return "", errors.New("unterminated multi-line string")
+}
+
+func (p *tomlParser) parseEscape(value *strings.Builder) error {
+	if p.eof() {
+		return errors.New("unterminated escape sequence")
+	}
+
+	switch c := p.peek(); c {
+	case 'n':
+		value.WriteByte('\n')
+	case 'r':
+		value.WriteByte('\r')
+	case 't':
+		value.WriteByte('\t')
+	case '"', '\\':
+		value.WriteByte(c)
+	case '\n':
+		for !p.eof() && strings.ContainsRune(" \t\r\n", rune(p.peek())) {
+			p.pos++
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid escape sequence \\%c", c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *tomlParser) skipBlank() {
+	for !p.eof() {
+		switch p.peek() {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) skipSpaces() {
+	for !p.eof() && isBlank(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	for !p.eof() && p.peek() != '\n' {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *tomlParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *tomlParser) error(err error) error {
+	return &ConfigError{Line: lineAt(p.input, p.pos), Err: err}
+}
+
+func tomlTable(root map[string]any, keys []string) (map[string]any, error) {
+	table := root
+	for _, key := range keys {
+		switch existing := table[key].(type) {
+		case nil:
+			child := make(map[string]any)
+			table[key] = child
+			table = child
+		case map[string]any:
+			table = existing
+		case []any:
+			if len(existing) == 0 {
+				return nil, fmt.Errorf("key %q is not a table", key)
+			}
+			last, ok := existing[len(existing)-1].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("key %q is not a table", key)
+			}
+			table = last
+		default:
+			return nil, fmt.Errorf("key %q is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+func isTOMLBareKey(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
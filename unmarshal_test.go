@@ -0,0 +1,129 @@
+package envconfig
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/c2fo/testify/assert"
+)
+
+type tenantID struct {
+	Region string
+	Number int
+}
+
+func (id *tenantID) UnmarshalText(text []byte) error {
+	region, number, ok := strings.Cut(string(text), "-")
+	if !ok {
+		return fmt.Errorf("invalid tenant id: %q", text)
+	}
+	id.Region = region
+	_, err := fmt.Sscan(number, &id.Number)
+	return err
+}
+
+func TestTextUnmarshalerField(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Addr    netip.Addr
+		Big     *big.Int
+		Tenant  tenantID
+		Pointer *tenantID
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"ADDR":    "10.0.0.1",
+		"BIG":     "123456789012345678901234567890",
+		"TENANT":  "eu-42",
+		"POINTER": "us-7",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), spec.Addr)
+	assert.Equal(t, "123456789012345678901234567890", spec.Big.String())
+	assert.Equal(t, tenantID{Region: "eu", Number: 42}, spec.Tenant)
+	assert.Equal(t, tenantID{Region: "us", Number: 7}, *spec.Pointer)
+}
+
+func TestTextUnmarshalerIsNotWalked(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Tenant tenantID
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"TENANT_REGION": "eu"}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, tenantID{}, spec.Tenant)
+}
+
+func TestTextUnmarshalerCollections(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Inline    []netip.Addr
+		InlineMap map[string]tenantID
+		Indexed   []tenantID
+		Templated map[netip.Addr]tenantID
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"INLINE":             "10.0.0.1, 10.0.0.2",
+		"INLINE_MAP":         "a:eu-1,b:us-2",
+		"INDEXED_1":          "eu-3",
+		"TEMPLATED_10.0.0.3": "us-4",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")}, spec.Inline)
+	assert.Equal(t, map[string]tenantID{"a": {"eu", 1}, "b": {"us", 2}}, spec.InlineMap)
+	assert.Equal(t, []tenantID{{}, {"eu", 3}}, spec.Indexed)
+	assert.Equal(t, map[netip.Addr]tenantID{netip.MustParseAddr("10.0.0.3"): {"us", 4}}, spec.Templated)
+}
+
+func TestTextUnmarshalerError(t *testing.T) {
+	t.Parallel()
+
+	type TestSpec struct {
+		Addr netip.Addr
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{"ADDR": "not-an-address"}
+
+	assert.Error(t, InitWithOptions(&TestSpec{}, options))
+}
+
+func TestTextUnmarshalerEmbedded(t *testing.T) {
+	t.Parallel()
+
+	type Endpoint struct {
+		netip.Addr
+		Port int
+	}
+	type Wrapped struct {
+		netip.Addr
+	}
+	type TestSpec struct {
+		Endpoint Endpoint
+		Wrapped  Wrapped
+	}
+	options := DefaultOptions()
+	options.Source = MapSource{
+		"ENDPOINT_ADDR": "10.0.0.1",
+		"ENDPOINT_PORT": "8080",
+		"WRAPPED":       "10.0.0.2",
+	}
+
+	spec := TestSpec{}
+	assert.NoError(t, InitWithOptions(&spec, options))
+	assert.Equal(t, Endpoint{netip.MustParseAddr("10.0.0.1"), 8080}, spec.Endpoint)
+	assert.Equal(t, Wrapped{netip.MustParseAddr("10.0.0.2")}, spec.Wrapped)
+}
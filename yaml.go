@@ -0,0 +1,458 @@
+package envconfig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func newYAMLParser(input string) *yamlParser {
+	raw := strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	lines := make([]yamlLine, 0, len(raw))
+	for i, line := range raw {
+		text := strings.TrimLeft(line, " ")
+		lines = append(lines, yamlLine{number: i + 1, indent: len(line) - len(text), text: text})
+	}
+	return &yamlParser{lines: lines}
+}
+
+func (p *yamlParser) parse() (any, error) {
+	p.skipIgnorable()
+	if p.eof() {
+		return map[string]any{}, nil
+	}
+
+	document, err := p.parseNode(p.current().indent)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipIgnorable()
+	if !p.eof() {
+		return nil, p.error(errors.New("unexpected content"))
+	}
+	return document, nil
+}
+
+func (p *yamlParser) parseNode(indent int) (any, error) {
+	if strings.HasPrefix(p.current().text, "\t") {
+		return nil, p.error(errors.New("tabs are not allowed for indentation"))
+	}
+	if isYAMLSequenceItem(p.current().text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseSequence(indent int) (any, error) {
+	values := make([]any, 0)
+	for {
+		p.skipIgnorable()
+		if p.eof() || p.current().indent < indent || !isYAMLSequenceItem(p.current().text) {
+			return values, nil
+		}
+
+		line := p.current()
+		if line.indent > indent {
+			return nil, p.error(errors.New("unexpected indentation"))
+		}
+
+		content := strings.TrimLeft(line.text[1:], " ")
+		offset := len(line.text) - len(content)
+
+		var value any
+		var err error
+		switch {
+		case stripYAMLComment(content) == "":
+			p.pos++
+			value, err = p.parseChild(indent)
+		case isYAMLSequenceItem(content):
+			p.lines[p.pos] = yamlLine{number: line.number, indent: indent + offset, text: content}
+			value, err = p.parseSequence(indent + offset)
+		case isYAMLMappingEntry(content):
+			p.lines[p.pos] = yamlLine{number: line.number, indent: indent + offset, text: content}
+			value, err = p.parseMapping(indent + offset)
+		default:
+			value, err = p.parseInline(content, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+}
+
+func (p *yamlParser) parseMapping(indent int) (any, error) {
+	values := make(map[string]any)
+	for {
+		p.skipIgnorable()
+		if p.eof() || p.current().indent < indent {
+			return values, nil
+		}
+
+		line := p.current()
+		if line.indent > indent {
+			return nil, p.error(errors.New("unexpected indentation"))
+		}
+		if isYAMLSequenceItem(line.text) {
+			return nil, p.error(errors.New("unexpected sequence item"))
+		}
+
+		key, rest, ok := splitYAMLMappingEntry(line.text)
+		if !ok {
+			return nil, p.error(fmt.Errorf("expected 'key: value' but got %q", line.text))
+		}
+		if _, ok := values[key]; ok {
+			return nil, p.error(fmt.Errorf("key %q is already defined", key))
+		}
+
+		var value any
+		var err error
+		if stripYAMLComment(rest) == "" {
+			p.pos++
+			p.skipIgnorable()
+			if !p.eof() && p.current().indent == indent && isYAMLSequenceItem(p.current().text) {
+				value, err = p.parseSequence(indent)
+			} else {
+				value, err = p.parseChild(indent)
+			}
+		} else {
+			value, err = p.parseInline(rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+}
+
+func (p *yamlParser) parseChild(indent int) (any, error) {
+	p.skipIgnorable()
+	if p.eof() || p.current().indent <= indent {
+		return nil, nil
+	}
+	return p.parseNode(p.current().indent)
+}
+
+func (p *yamlParser) parseInline(text string, indent int) (any, error) {
+	text = stripYAMLComment(text)
+	switch {
+	case strings.HasPrefix(text, "|"), strings.HasPrefix(text, ">"):
+		return p.parseBlockScalar(text, indent), nil
+	case strings.HasPrefix(text, "["), strings.HasPrefix(text, "{"):
+		parser := yamlFlowParser{input: text}
+		value, err := parser.parse()
+		if err != nil {
+			return nil, p.error(err)
+		}
+		p.pos++
+		return value, nil
+	default:
+		value, err := parseYAMLScalar(text)
+		if err != nil {
+			return nil, p.error(err)
+		}
+		p.pos++
+		return value, nil
+	}
+}
+
+func (p *yamlParser) parseBlockScalar(header string, indent int) string {
+	p.pos++
+
+	lines := make([]string, 0)
+	blockIndent := -1
+	for !p.eof() {
+		line := p.current()
+		if line.text == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		if line.indent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = line.indent
+		}
+		lines = append(lines, strings.Repeat(" ", max(line.indent-blockIndent, 0))+line.text)
+		p.pos++
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	separator := "\n"
+	if strings.HasPrefix(header, ">") {
+		separator = " "
+	}
+	value := strings.Join(lines, separator)
+	if !strings.HasSuffix(header, "-") && value != "" {
+		value += "\n"
+	}
+	return value
+}
+
+func (p *yamlParser) skipIgnorable() {
+	for !p.eof() {
+		text := p.current().text
+		if text != "" && !strings.HasPrefix(text, "#") && text != "---" {
+			return
+		}
+		p.pos++
+	}
+}
+
+func (p *yamlParser) current() yamlLine {
+	return p.lines[p.pos]
+}
+
+func (p *yamlParser) eof() bool {
+	return p.pos >= len(p.lines)
+}
+
+func (p *yamlParser) error(err error) error {
+	line := len(p.lines)
+	if !p.eof() {
+		line = p.current().number
+	}
+	return &ConfigError{Line: line, Err: err}
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isYAMLMappingEntry(text string) bool {
+	_, _, ok := splitYAMLMappingEntry(text)
+	return ok
+}
+
+func splitYAMLMappingEntry(text string) (string, string, bool) {
+	if strings.HasPrefix(text, "\"") || strings.HasPrefix(text, "'") {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		key, err := parseYAMLScalar(text[:end+2])
+		rest := strings.TrimLeft(text[end+2:], " ")
+		if err != nil || !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return fmt.Sprint(key), strings.TrimSpace(rest[1:]), true
+	}
+
+	if strings.HasPrefix(text, "#") || strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+
+	index := strings.Index(text, ": ")
+	if index < 0 {
+		if !strings.HasSuffix(text, ":") {
+			return "", "", false
+		}
+		index = len(text) - 1
+	}
+	return strings.TrimSpace(text[:index]), strings.TrimSpace(text[index+1:]), true
+}
+
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || isBlank(text[i-1])):
+			return strings.TrimSpace(text[:i])
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+func parseYAMLScalar(text string) (any, error) {
+	switch {
+	case text == "~", text == "null", text == "Null", text == "NULL":
+		return nil, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, errors.New("unterminated single-quoted scalar")
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	case strings.HasPrefix(text, "\""):
+		if len(text) < 2 || !strings.HasSuffix(text, "\"") {
+			return nil, errors.New("unterminated double-quoted scalar")
+		}
+		return unescapeYAML(text[1 : len(text)-1])
+	default:
+		return text, nil
+	}
+}
+
+func unescapeYAML(text string) (string, error) {
+	var value strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != '\\' {
+			value.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i >= len(text) {
+			return "", errors.New("unterminated escape sequence")
+		}
+		switch text[i] {
+		case 'n':
+			value.WriteByte('\n')
+		case 'r':
+			value.WriteByte('\r')
+		case 't':
+			value.WriteByte('\t')
+		case '0':
+			value.WriteByte(0)
+		case '"', '\\', '/':
+			value.WriteByte(text[i])
+		default:
+			return "", fmt.Errorf("invalid escape sequence \\%c", text[i])
+		}
+	}
+	return value.String(), nil
+}
+
+type yamlFlowParser struct {
+	input string
+	pos   int
+}
+
+func (p *yamlFlowParser) parse() (any, error) {
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q after flow collection", p.input[p.pos:])
+	}
+	return value, nil
+}
+
+func (p *yamlFlowParser) parseValue() (any, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, errors.New("unexpected end of flow collection")
+	}
+
+	switch p.input[p.pos] {
+	case '[':
+		p.pos++
+		values := make([]any, 0)
+		for {
+			p.skipSpaces()
+			if p.consume(']') {
+				return values, nil
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+			if err := p.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		p.pos++
+		values := make(map[string]any)
+		for {
+			p.skipSpaces()
+			if p.consume('}') {
+				return values, nil
+			}
+			key, err := p.parseScalar(":,}")
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpaces()
+			if !p.consume(':') {
+				return nil, fmt.Errorf("expected ':' after key %v", key)
+			}
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			values[fmt.Sprint(key)] = value
+			if err := p.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return p.parseScalar(",]}")
+	}
+}
+
+func (p *yamlFlowParser) parseScalar(terminators string) (any, error) {
+	p.skipSpaces()
+	start := p.pos
+	if p.pos < len(p.input) && (p.input[p.pos] == '"' || p.input[p.pos] == '\'') {
+		quote := p.input[p.pos]
+		p.pos++
+		for p.pos < len(p.input) && p.input[p.pos] != quote {
+			if quote == '"' && p.input[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		p.pos++
+		if p.pos > len(p.input) {
+			return nil, errors.New("unterminated quoted scalar")
+		}
+	} else {
+		for p.pos < len(p.input) && !strings.ContainsRune(terminators, rune(p.input[p.pos])) {
+			p.pos++
+		}
+	}
+	return parseYAMLScalar(strings.TrimSpace(p.input[start:p.pos]))
+}
+
+func (p *yamlFlowParser) separator(closing byte) error {
+	p.skipSpaces()
+	if p.consume(',') {
+		return nil
+	}
+	if p.pos < len(p.input) && p.input[p.pos] == closing {
+		return nil
+	}
+	return fmt.Errorf("expected ',' or '%c' in flow collection", closing)
+}
+
+func (p *yamlFlowParser) consume(c byte) bool {
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *yamlFlowParser) skipSpaces() {
+	for p.pos < len(p.input) && isBlank(p.input[p.pos]) {
+		p.pos++
+	}
+}